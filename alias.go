@@ -0,0 +1,165 @@
+package depgraph
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Alias declares that alias refers to the same node as canonical.
+// From then on, any reference to alias, either as an element or as a dependency, is treated
+// as a reference to canonical, and the resolution output only uses the canonical name.
+// Existing references get rewritten right away: if alias has already been added as an element,
+// it is merged into canonical, taking canonical's place if canonical has not been added yet.
+// An alias may itself be used as the canonical name of another alias.
+// An error is returned if the alias would end up referring to itself.
+func (dg *DependencyGraph[T]) Alias(canonical, alias T) error {
+	canonical = dg.resolveAlias(canonical)
+	if canonical == alias {
+		return fmt.Errorf("aliasing \"%v\" to itself: %w", alias, ErrAliasCycle)
+	}
+
+	dg.mutate()
+
+	// The alias map is shared with the snapshots, so it is replaced rather than modified.
+	aliases := maps.Clone(dg.aliases)
+	if aliases == nil {
+		aliases = map[T]T{}
+	}
+
+	for a, c := range aliases {
+		if c == alias {
+			aliases[a] = canonical
+		}
+	}
+
+	aliases[alias] = canonical
+	dg.aliases = aliases
+
+	// Merge the aliased element into the canonical one.
+	if aliased, ok := dg.edgeMap[alias]; ok {
+		delete(dg.edgeMap, alias)
+
+		if _, ok := dg.edgeMap[canonical]; ok {
+			dg.edges = slices.DeleteFunc(dg.edges, func(e *depEdge[T]) bool {
+				return e == aliased
+			})
+		} else {
+			aliased.name = canonical
+			dg.edgeMap[canonical] = aliased
+		}
+
+		dg.Add(canonical, aliased.depOrder...)
+
+		for dep, cost := range aliased.costs {
+			dg.AddCost(canonical, dg.resolveAlias(dep), cost)
+		}
+	}
+
+	// Rewrite the dependencies on the alias.
+	for _, edge := range dg.edges {
+		if _, ok := edge.deps[alias]; !ok {
+			continue
+		}
+
+		delete(edge.deps, alias)
+		idx := slices.Index(edge.depOrder, alias)
+
+		_, weak := edge.weak[alias]
+		delete(edge.weak, alias)
+
+		if _, ok := edge.deps[canonical]; ok {
+			edge.depOrder = slices.Delete(edge.depOrder, idx, idx+1)
+
+			// The dependency stays weak only if it has been weak under both names.
+			if !weak {
+				delete(edge.weak, canonical)
+			}
+		} else {
+			edge.deps[canonical] = struct{}{}
+			edge.depOrder[idx] = canonical
+
+			if weak {
+				edge.weak[canonical] = struct{}{}
+			}
+		}
+
+		if cost, ok := edge.costs[alias]; ok {
+			delete(edge.costs, alias)
+			edge.costs[canonical] = cost
+		}
+	}
+
+	return nil
+}
+
+// Canonical returns the canonical name of a node: if it is an alias,
+// the name it refers to is returned; otherwise, the node itself is returned.
+func (dg *DependencyGraph[T]) Canonical(name T) T {
+	return dg.resolveAlias(name)
+}
+
+// resolveAlias maps an alias to its canonical name.
+// Since aliases always refer to canonical names directly, a single lookup is enough.
+func (dg *DependencyGraph[T]) resolveAlias(name T) T {
+	if canonical, ok := dg.aliases[name]; ok {
+		return canonical
+	}
+
+	return name
+}
+
+// canonicalize maps an element and its dependencies to their canonical names,
+// without modifying the original dependency slice.
+func (dg *DependencyGraph[T]) canonicalize(name T, deps []T) (T, []T) {
+	res := make([]T, len(deps))
+	for i, dep := range deps {
+		res[i] = dg.resolveAlias(dep)
+	}
+
+	return dg.resolveAlias(name), res
+}
+
+// CoalesceLeaves merges the leaf nodes, meaning the nodes without any dependencies,
+// that share the same key and are depended upon by exactly the same set of nodes.
+// Of each group of such leaves, the first one in the edge list order survives,
+// and the others become its aliases, so that their dependents refer to the surviving node instead.
+// Leaves that provide or require capabilities are left as is.
+// It returns the number of nodes that have been merged away.
+func (dg *DependencyGraph[T]) CoalesceLeaves(key func(name T) string) int {
+	type group struct {
+		key        string
+		dependents string
+	}
+
+	_, dependents := dg.adjacency()
+	survivors := map[group]T{}
+	merges := [][2]T{}
+
+	for i, edge := range dg.edges {
+		if len(edge.depOrder) > 0 || len(edge.provides) > 0 || len(edge.requires) > 0 {
+			continue
+		}
+
+		// The dependents are listed in the edge list order, so their encoding is canonical.
+		ids := []byte{}
+		for _, d := range dependents[i] {
+			ids = binary.AppendUvarint(ids, uint64(d))
+		}
+
+		g := group{key: key(edge.name), dependents: string(ids)}
+		if survivor, ok := survivors[g]; ok {
+			merges = append(merges, [2]T{survivor, edge.name})
+		} else {
+			survivors[g] = edge.name
+		}
+	}
+
+	for _, m := range merges {
+		// The survivor is always present and distinct from the leaf, so aliasing cannot fail.
+		_ = dg.Alias(m[0], m[1])
+	}
+
+	return len(merges)
+}
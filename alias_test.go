@@ -0,0 +1,154 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestAlias tests that dependencies on aliases are treated as dependencies on canonical nodes.
+func TestAlias(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "pg", "cache")
+	dg.Add("pg", "disk")
+	dg.Add("disk")
+	dg.Add("worker", "postgres", "pg")
+
+	err := dg.Alias("postgres", "pg")
+	if err != nil {
+		t.Fatalf("aliasing node: %v", err)
+	}
+
+	err = dg.Alias("redis", "cache")
+	if err != nil {
+		t.Fatalf("aliasing unknown node: %v", err)
+	}
+
+	dg.Add("redis")
+	dg.Add("cron", "pg", "cache")
+
+	if deps := dg.Dependencies("worker"); !slices.Equal(deps, []string{"postgres"}) {
+		t.Fatalf("aliased dependencies merged incorrectly: %v", deps)
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with aliases: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"disk", "redis", "postgres", "worker", "app", "cron"}) {
+		t.Fatalf("graph with aliases resolved incorrectly: %v", res)
+	}
+
+	// Chained aliases refer to the same canonical node.
+	err = dg.Alias("pg", "db")
+	if err != nil {
+		t.Fatalf("aliasing alias: %v", err)
+	}
+
+	if dg.Canonical("db") != "postgres" || dg.Canonical("app") != "app" {
+		t.Fatalf("canonical names resolved incorrectly")
+	}
+
+	err = dg.Alias("db", "postgres")
+	if !errors.Is(err, ErrAliasCycle) {
+		t.Fatalf("created alias cycle: %v", err)
+	}
+}
+
+// TestAliasMerge tests that an aliased element gets merged into an existing canonical one.
+func TestAliasMerge(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "X")
+	dg.Add("B", "C")
+	dg.Add("C")
+	dg.Add("X")
+
+	snap := dg.Snapshot()
+
+	err := dg.Alias("A", "B")
+	if err != nil {
+		t.Fatalf("aliasing node: %v", err)
+	}
+
+	if deps := dg.Dependencies("A"); !slices.Equal(deps, []string{"X", "C"}) {
+		t.Fatalf("aliased element merged incorrectly: %v", deps)
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving merged graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"C", "X", "A"}) {
+		t.Fatalf("merged graph resolved incorrectly: %v", res)
+	}
+
+	dg.Restore(snap)
+
+	if dg.Canonical("B") != "B" || dg.Dependencies("B") == nil {
+		t.Fatalf("alias survived restoring snapshot")
+	}
+}
+
+// TestCoalesceLeaves tests that equivalent leaves with identical dependents get merged.
+func TestCoalesceLeaves(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "libc-1", "libc-2", "zlib-1")
+	dg.Add("tool", "libc-3", "zlib-2")
+	dg.Add("libc-1")
+	dg.Add("libc-2")
+	dg.Add("libc-3")
+	dg.Add("zlib-1")
+	dg.Add("zlib-2")
+	dg.Add("cli", "tool", "libc-4")
+	dg.Add("libc-4", "kernel")
+	dg.Add("kernel")
+
+	merged := dg.CoalesceLeaves(func(name string) string {
+		base, _, _ := strings.Cut(name, "-")
+		return base
+	})
+
+	// libc-3 and zlib-2 have different keys; libc-4 is not a leaf.
+	if merged != 1 {
+		t.Fatalf("unexpected number of merged leaves: %d", merged)
+	}
+
+	if deps := dg.Dependencies("app"); !slices.Equal(deps, []string{"libc-1", "zlib-1"}) {
+		t.Fatalf("dependencies rewritten incorrectly: %v", deps)
+	}
+
+	if dg.Canonical("libc-2") != "libc-1" {
+		t.Fatalf("merged leaf refers to %q", dg.Canonical("libc-2"))
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving coalesced graph: %v", err)
+	}
+
+	if len(res) != 9 || slices.Contains(res, "libc-2") {
+		t.Fatalf("coalesced graph resolved incorrectly: %v", res)
+	}
+}
+
+// TestAliasCosts tests that the dependency costs survive aliasing, on both sides of the dependencies.
+func TestAliasCosts(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("X")
+	dg.AddCost("b", "X", 2)
+	dg.AddCost("C", "b", 3)
+
+	err := dg.Alias("A", "b")
+	if err != nil {
+		t.Fatalf("aliasing node with costs: %v", err)
+	}
+
+	path, cost, ok := dg.CheapestPath("C", "X")
+	if !ok || cost != 5 || !slices.Equal(path, []string{"C", "A", "X"}) {
+		t.Fatalf("costs of aliased node merged incorrectly: %v, %v, %v", path, cost, ok)
+	}
+}
@@ -0,0 +1,483 @@
+package depgraph
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// Diamonds returns the (top, bottom) pairs of nodes, where bottom is reachable from top
+// through more than one of top's direct dependencies, including the case when bottom is
+// a direct dependency itself and is also reachable through another one.
+// Diamonds often indicate redundant wiring.
+// The pairs are ordered by top in the edge list order, then by bottom in the order of discovery.
+func (dg *DependencyGraph[T]) Diamonds() [][2]T {
+	res := [][2]T{}
+
+	for _, top := range dg.edges {
+		counts := map[T]int{}
+		bottoms := []T{}
+
+		for _, dep := range top.depOrder {
+			for _, node := range dg.reachable(dep) {
+				counts[node]++
+
+				if counts[node] == 2 {
+					bottoms = append(bottoms, node)
+				}
+			}
+		}
+
+		for _, bottom := range bottoms {
+			res = append(res, [2]T{top.name, bottom})
+		}
+	}
+
+	return res
+}
+
+// reachable returns the nodes, which are reachable from a node by following the dependencies,
+// including the node itself, in a deterministic order.
+// The traversal uses an explicit stack, so that deep chains do not exhaust the call stack.
+func (dg *DependencyGraph[T]) reachable(from T) []T {
+	visited := map[T]struct{}{from: {}}
+	res := []T{}
+	stack := []T{from}
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		res = append(res, cur)
+
+		edge, ok := dg.edgeMap[cur]
+		if !ok {
+			continue
+		}
+
+		// Push the dependencies in reverse, so that they are visited in their insertion order.
+		for i := len(edge.depOrder) - 1; i >= 0; i-- {
+			dep := edge.depOrder[i]
+
+			if _, ok := visited[dep]; !ok {
+				visited[dep] = struct{}{}
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	return res
+}
+
+// OrphansFrom returns the graph's elements, which are not reachable by following the dependencies
+// from any of the given roots; the roots themselves are considered reachable.
+// Orphans usually indicate leftovers of a dropped target.
+// The elements are returned in the edge list order.
+func (dg *DependencyGraph[T]) OrphansFrom(roots ...T) []T {
+	reached := map[T]struct{}{}
+
+	for _, root := range roots {
+		if _, ok := reached[root]; ok {
+			continue
+		}
+
+		for _, node := range dg.reachable(root) {
+			reached[node] = struct{}{}
+		}
+	}
+
+	res := []T{}
+	for _, edge := range dg.edges {
+		if _, ok := reached[edge.name]; !ok {
+			res = append(res, edge.name)
+		}
+	}
+
+	return res
+}
+
+// ConnectedComponents splits the graph's elements into weakly connected components,
+// treating the dependencies as undirected edges; unknown dependencies are ignored.
+// The components are ordered by their first element, and the elements of each component
+// follow the edge list order.
+func (dg *DependencyGraph[T]) ConnectedComponents() [][]T {
+	parent := make([]int, len(dg.edges))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	find := func(i int) int {
+		for parent[i] != i {
+			// Halve the path on the way up.
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+
+		return i
+	}
+
+	for i, deps := range dg.depIndices() {
+		for _, j := range deps {
+			a, b := find(i), find(j)
+
+			// Keep the lowest index as the root, so that components are ordered by their first element.
+			if a < b {
+				parent[b] = a
+			} else {
+				parent[a] = b
+			}
+		}
+	}
+
+	res := [][]T{}
+	slot := map[int]int{}
+
+	for i, edge := range dg.edges {
+		root := find(i)
+
+		s, ok := slot[root]
+		if !ok {
+			s = len(res)
+			slot[root] = s
+			res = append(res, nil)
+		}
+
+		res[s] = append(res[s], edge.name)
+	}
+
+	return res
+}
+
+// UndirectedNeighbors returns the elements adjacent to a node, when the dependencies are
+// treated as undirected edges: its direct dependencies, in the order in which they have been added,
+// followed by its direct dependents, in the edge list order.
+// Every neighbor is returned once, and the node is never its own neighbor;
+// unknown dependencies are ignored.
+// If the node is not present in the graph, it returns nil.
+func (dg *DependencyGraph[T]) UndirectedNeighbors(name T) []T {
+	edge, ok := dg.edgeMap[name]
+	if !ok {
+		return nil
+	}
+
+	seen := map[T]struct{}{name: {}}
+	res := []T{}
+
+	for _, dep := range edge.depOrder {
+		if _, ok := dg.edgeMap[dep]; !ok {
+			continue
+		}
+
+		if _, ok := seen[dep]; !ok {
+			seen[dep] = struct{}{}
+			res = append(res, dep)
+		}
+	}
+
+	for _, other := range dg.edges {
+		if _, ok := other.deps[name]; !ok {
+			continue
+		}
+
+		if _, ok := seen[other.name]; !ok {
+			seen[other.name] = struct{}{}
+			res = append(res, other.name)
+		}
+	}
+
+	return res
+}
+
+// validateConnected checks that the graph consists of a single weakly connected component.
+func (dg *DependencyGraph[T]) validateConnected() error {
+	components := dg.ConnectedComponents()
+	if len(components) <= 1 {
+		return nil
+	}
+
+	reps := make([]string, len(components))
+	for i, component := range components {
+		reps[i] = fmt.Sprintf("\"%v\"", component[0])
+	}
+
+	return fmt.Errorf("graph has %d components, represented by %s: %w",
+		len(components), strings.Join(reps, ", "), ErrDisconnectedGraph)
+}
+
+// ReachCentrality computes a reach-based centrality score for each of the graph's elements:
+// the number of (source, target) pairs of other elements, such that the source reaches the target
+// through the element, that is, the source transitively depends on the element,
+// which in turn transitively depends on the target.
+// Elements with high scores are the intermediaries most of the graph relies upon.
+// The score is computed exactly, in a quadratic time; unknown dependencies are ignored.
+func (dg *DependencyGraph[T]) ReachCentrality() map[T]int {
+	_, dependents := dg.adjacency()
+	deps := dg.depIndices()
+
+	n := len(dg.edges)
+	res := make(map[T]int, n)
+
+	// Stamps avoid clearing the visited sets between traversals.
+	down := make([]int, n)
+	up := make([]int, n)
+	stack := make([]int, 0, n)
+
+	traverse := func(from int, adj [][]int, visited []int, stamp int) []int {
+		reached := []int{}
+		stack = append(stack[:0], from)
+		visited[from] = stamp
+
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			for _, next := range adj[cur] {
+				if visited[next] != stamp {
+					visited[next] = stamp
+					reached = append(reached, next)
+					stack = append(stack, next)
+				}
+			}
+		}
+
+		return reached
+	}
+
+	for v, edge := range dg.edges {
+		stamp := v + 1
+		targets := traverse(v, deps, down, stamp)
+		sources := traverse(v, dependents, up, stamp)
+
+		// The traversals never reach v itself, but in a cycle, an element may be both a source and a target;
+		// pairing it with itself makes no sense.
+		both := 0
+		for _, i := range sources {
+			if down[i] == stamp {
+				both++
+			}
+		}
+
+		res[edge.name] = len(sources)*len(targets) - both
+	}
+
+	return res
+}
+
+// LayerViolations returns the dependencies, which go against a declared layering:
+// every node is assigned a layer by layerOf, where higher layers may depend on lower ones,
+// so a dependency of a node on a node from a higher layer is a violation.
+// Dependencies within a single layer are allowed.
+// Unlike circular dependencies, violations are detected on every single edge,
+// even when it does not form a cycle.
+// Each violation is returned as a (node, dependency) pair; the pairs follow the edge list order,
+// and then the order in which the dependencies have been added.
+func (dg *DependencyGraph[T]) LayerViolations(layerOf func(T) int) [][2]T {
+	res := [][2]T{}
+
+	for _, edge := range dg.edges {
+		layer := layerOf(edge.name)
+
+		for _, dep := range edge.depOrder {
+			if layerOf(dep) > layer {
+				res = append(res, [2]T{edge.name, dep})
+			}
+		}
+	}
+
+	return res
+}
+
+// DisallowedEdge is a dependency, which violates the policy given to ValidateEdges.
+type DisallowedEdge[T comparable] struct {
+	// From is the dependent node.
+	From T
+
+	// To is the dependency.
+	To T
+}
+
+// ValidateEdges checks every dependency of the graph against a policy, such as an architectural rule
+// over the categories of the nodes, and returns the ones, for which allowed returns false.
+// Unknown dependencies are checked as well.
+// The edges follow the edge list order, and then the order in which the dependencies have been added.
+func (dg *DependencyGraph[T]) ValidateEdges(allowed func(from, to T) bool) []DisallowedEdge[T] {
+	res := []DisallowedEdge[T]{}
+
+	for _, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			if !allowed(edge.name, dep) {
+				res = append(res, DisallowedEdge[T]{From: edge.name, To: dep})
+			}
+		}
+	}
+
+	return res
+}
+
+// IsLinearChain reports whether a node, along with its transitive dependencies, forms a single linear chain,
+// where every node has at most one dependency, so that there is nothing to process in parallel.
+// Unknown dependencies count as nodes of the chain as well.
+// An error is returned if the node is not present in the graph,
+// or if its transitive dependencies contain a circular dependency.
+func (dg *DependencyGraph[T]) IsLinearChain(name T) (bool, error) {
+	if _, ok := dg.edgeMap[name]; !ok {
+		return false, fmt.Errorf("looking up node \"%v\": %w", name, ErrUnknownNode)
+	}
+
+	closure := map[T]struct{}{}
+	linear := true
+
+	for _, node := range dg.reachable(name) {
+		closure[node] = struct{}{}
+
+		if edge, ok := dg.edgeMap[node]; ok && len(edge.depOrder) > 1 {
+			linear = false
+		}
+	}
+
+	// Resolving the known part of the closure on its own detects circular dependencies.
+	sub := dg.derive(func(node T) bool {
+		_, ok := closure[node]
+		return ok
+	}, func(_, dep T) bool {
+		_, ok := dg.edgeMap[dep]
+		return ok
+	})
+
+	_, err := sub.Resolve()
+	if err != nil {
+		return false, fmt.Errorf("resolving dependencies of \"%v\": %w", name, err)
+	}
+
+	return linear, nil
+}
+
+// ArticulationPoints returns the graph's cut elements, that is, the elements whose removal
+// splits their weakly connected component into several ones; the direction of dependencies
+// is ignored, and so are unknown dependencies.
+// Such elements are the single points of failure, which the rest of the graph hangs together by.
+// The elements are returned in the edge list order.
+func (dg *DependencyGraph[T]) ArticulationPoints() []T {
+	n := len(dg.edges)
+
+	adj := make([][]int, n)
+	for i, deps := range dg.depIndices() {
+		for _, j := range deps {
+			if i != j {
+				adj[i] = append(adj[i], j)
+				adj[j] = append(adj[j], i)
+			}
+		}
+	}
+
+	type frame struct {
+		node, parent, next int
+	}
+
+	// Discovery times start at 1, so that a zero means an unvisited element.
+	disc := make([]int, n)
+	low := make([]int, n)
+	cut := make([]bool, n)
+	stack := []frame{}
+	clock := 0
+
+	// The depth-first search is iterative, since the graph may be arbitrarily deep.
+	for root := range n {
+		if disc[root] != 0 {
+			continue
+		}
+
+		clock++
+		disc[root], low[root] = clock, clock
+		stack = append(stack, frame{node: root, parent: -1})
+		children := 0
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if top.next < len(adj[top.node]) {
+				next := adj[top.node][top.next]
+				top.next++
+
+				switch {
+				case next == top.parent:
+				case disc[next] == 0:
+					clock++
+					disc[next], low[next] = clock, clock
+					stack = append(stack, frame{node: next, parent: top.node})
+				default:
+					low[top.node] = min(low[top.node], disc[next])
+				}
+
+				continue
+			}
+
+			cur := *top
+			stack = stack[:len(stack)-1]
+
+			if cur.parent < 0 {
+				continue
+			}
+
+			low[cur.parent] = min(low[cur.parent], low[cur.node])
+
+			if cur.parent == root {
+				children++
+			} else if low[cur.node] >= disc[cur.parent] {
+				cut[cur.parent] = true
+			}
+		}
+
+		// The root has no ancestors to fall back on, so it only cuts the component
+		// if it has several subtrees.
+		cut[root] = children > 1
+	}
+
+	res := []T{}
+	for i, edge := range dg.edges {
+		if cut[i] {
+			res = append(res, edge.name)
+		}
+	}
+
+	return res
+}
+
+// ByFootprint returns the graph's elements sorted by the size of their transitive dependency closure,
+// in descending order; ties are broken by the edge list order.
+// The elements with the largest footprint are the most expensive to build and the riskiest to change.
+// An element does not count towards its own footprint, even if it is part of a cycle;
+// unknown dependencies are ignored.
+// The closures are computed once per strongly connected component, in dependency order,
+// rather than by a separate traversal from every element.
+func (dg *DependencyGraph[T]) ByFootprint() []T {
+	comp, members, closures := dg.componentClosures()
+
+	footprints := make([]int, len(members))
+	for c, closure := range closures {
+		footprints[c] = len(members[c]) - 1
+
+		for w, set := range closure {
+			for set != 0 {
+				footprints[c] += len(members[w*64+bits.TrailingZeros64(set)])
+				set &= set - 1
+			}
+		}
+	}
+
+	order := make([]int, len(dg.edges))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return footprints[comp[b]] - footprints[comp[a]]
+	})
+
+	res := make([]T, len(order))
+	for i, e := range order {
+		res[i] = dg.edges[e].name
+	}
+
+	return res
+}
@@ -0,0 +1,278 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestDiamonds tests that diamonds get detected, including the ones formed by redundant edges.
+func TestDiamonds(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "B", "C")
+	dg.Add("B", "D")
+	dg.Add("C", "D")
+	dg.Add("D", "E")
+	dg.Add("E")
+	dg.Add("F", "E", "D")
+	dg.Add("G", "B")
+
+	res := dg.Diamonds()
+	expected := [][2]string{{"A", "D"}, {"A", "E"}, {"F", "E"}}
+
+	if !slices.Equal(res, expected) {
+		t.Fatalf("diamonds detected incorrectly: %v", res)
+	}
+}
+
+// TestOrphansFrom tests that orphans are the nodes unreachable from all roots.
+func TestOrphansFrom(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "lib")
+	dg.Add("lib", "core")
+	dg.Add("core")
+	dg.Add("tool", "util")
+	dg.Add("util", "core")
+	dg.Add("old", "legacy")
+	dg.Add("legacy")
+
+	tbl := []struct {
+		roots []string
+		out   []string
+	}{
+		{roots: []string{}, out: []string{"app", "lib", "core", "tool", "util", "old", "legacy"}},
+		{roots: []string{"app"}, out: []string{"tool", "util", "old", "legacy"}},
+		{roots: []string{"app", "tool", "X"}, out: []string{"old", "legacy"}},
+		{roots: []string{"app", "tool", "old"}, out: []string{}},
+	}
+
+	for _, test := range tbl {
+		res := dg.OrphansFrom(test.roots...)
+		if !slices.Equal(res, test.out) {
+			t.Fatalf("orphans found incorrectly: roots = %v; output = %v; expected = %v", test.roots, res, test.out)
+		}
+	}
+}
+
+// TestReachCentrality tests the reach centrality scores on a small graph.
+func TestReachCentrality(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "C")
+	dg.Add("B", "C")
+	dg.Add("C", "D", "E")
+	dg.Add("D")
+	dg.Add("E", "X")
+
+	res := dg.ReachCentrality()
+	expected := map[string]int{"A": 0, "B": 0, "C": 4, "D": 0, "E": 0}
+
+	if !maps.Equal(res, expected) {
+		t.Fatalf("reach centrality computed incorrectly: %v", res)
+	}
+
+	// In a cycle A -> B -> C -> A, each element connects two ordered pairs of the other ones.
+	dg = NewDependencyGraph[string]()
+	dg.Add("A", "B")
+	dg.Add("B", "C")
+	dg.Add("C", "A")
+
+	res = dg.ReachCentrality()
+	if !maps.Equal(res, map[string]int{"A": 2, "B": 2, "C": 2}) {
+		t.Fatalf("reach centrality of a cycle computed incorrectly: %v", res)
+	}
+}
+
+// TestLayerViolations tests that dependencies on higher layers are reported.
+func TestLayerViolations(t *testing.T) {
+	layers := map[string]int{"ui": 2, "api": 2, "service": 1, "db": 0}
+
+	dg := NewDependencyGraph[string]()
+	dg.Add("ui", "service", "api")
+	dg.Add("service", "db")
+	dg.Add("db", "api", "service")
+
+	res := dg.LayerViolations(func(node string) int {
+		return layers[node]
+	})
+
+	if !slices.Equal(res, [][2]string{{"db", "api"}, {"db", "service"}}) {
+		t.Fatalf("layer violations detected incorrectly: %v", res)
+	}
+}
+
+// TestValidateEdges tests that every dependency violating the policy is reported.
+func TestValidateEdges(t *testing.T) {
+	kinds := map[string]string{"billing": "service", "auth": "service", "crypto": "library", "json": "library"}
+
+	dg := NewDependencyGraph[string]()
+	dg.Add("billing", "auth", "crypto")
+	dg.Add("crypto", "json", "auth", "X")
+	dg.Add("json")
+	dg.Add("auth", "json")
+
+	res := dg.ValidateEdges(func(from, to string) bool {
+		return kinds[to] == "library" || kinds[from] == "service" && kinds[to] == "service"
+	})
+
+	if !slices.Equal(res, []DisallowedEdge[string]{{From: "crypto", To: "auth"}, {From: "crypto", To: "X"}}) {
+		t.Fatalf("disallowed edges detected incorrectly: %v", res)
+	}
+}
+
+// TestIsLinearChain tests the detection of linear dependency chains.
+func TestIsLinearChain(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "B")
+	dg.Add("B", "C")
+	dg.Add("C", "X")
+	dg.Add("D", "A", "E")
+	dg.Add("E")
+	dg.Add("F", "G")
+	dg.Add("G", "H")
+	dg.Add("H", "G")
+
+	tbl := []struct {
+		name   string
+		linear bool
+	}{
+		{"A", true},
+		{"C", true},
+		{"E", true},
+		{"D", false},
+	}
+
+	for _, tc := range tbl {
+		linear, err := dg.IsLinearChain(tc.name)
+		if err != nil {
+			t.Fatalf("checking whether %q is a linear chain: %v", tc.name, err)
+		}
+
+		if linear != tc.linear {
+			t.Fatalf("linear chain detected incorrectly for %q: %v", tc.name, linear)
+		}
+	}
+
+	_, err := dg.IsLinearChain("F")
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("circular dependency not reported: %v", err)
+	}
+
+	_, err = dg.IsLinearChain("X")
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("unknown node not reported: %v", err)
+	}
+}
+
+// TestArticulationPoints tests that the elements holding the graph together get found.
+func TestArticulationPoints(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("api", "auth", "db")
+	dg.Add("auth", "db")
+	dg.Add("db", "disk")
+	dg.Add("disk")
+	dg.Add("worker", "api", "X")
+	dg.Add("cron", "worker")
+	dg.Add("single", "single")
+	dg.Add("A", "B")
+	dg.Add("B", "A")
+
+	if res := dg.ArticulationPoints(); !slices.Equal(res, []string{"api", "db", "worker"}) {
+		t.Fatalf("articulation points found incorrectly: %v", res)
+	}
+
+	// Closing the ring makes every element replaceable.
+	dg.Add("disk", "cron")
+
+	if res := dg.ArticulationPoints(); len(res) != 0 {
+		t.Fatalf("found articulation points in a ring: %v", res)
+	}
+}
+
+// TestByFootprint tests that elements get sorted by the size of their dependency closure,
+// including the elements being part of cycles.
+func TestByFootprint(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("log")
+	dg.Add("app", "api", "db")
+	dg.Add("api", "db", "log", "X")
+	dg.Add("db", "log")
+	dg.Add("tool", "log")
+	dg.Add("A", "B")
+	dg.Add("B", "A", "app")
+
+	// A and B depend on each other and on the whole app.
+	if res := dg.ByFootprint(); !slices.Equal(res, []string{"A", "B", "app", "api", "db", "tool", "log"}) {
+		t.Fatalf("elements sorted by footprint incorrectly: %v", res)
+	}
+
+	// Wide enough to span several words of the closure bitsets.
+	dg = NewDependencyGraph[string]()
+	for i := range 100 {
+		dg.Add(fmt.Sprint(i))
+
+		if i > 0 {
+			dg.Add(fmt.Sprint(i), fmt.Sprint(i-1))
+		}
+	}
+
+	res := dg.ByFootprint()
+	if res[0] != "99" || res[99] != "0" {
+		t.Fatalf("chain sorted by footprint incorrectly: %v", res)
+	}
+}
+
+// TestConnectedComponents tests that the elements get split into components regardless
+// of the direction of their dependencies.
+func TestConnectedComponents(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("C", "X")
+	dg.Add("B", "A")
+	dg.Add("D", "C")
+	dg.Add("E", "B", "D")
+	dg.Add("F", "F")
+
+	res := dg.ConnectedComponents()
+	if !slices.EqualFunc(res, [][]string{{"A", "C", "B", "D", "E"}, {"F"}}, slices.Equal) {
+		t.Fatalf("components computed incorrectly: %v", res)
+	}
+
+	if res := NewDependencyGraph[string]().ConnectedComponents(); len(res) != 0 {
+		t.Fatalf("components of an empty graph computed incorrectly: %v", res)
+	}
+}
+
+// TestUndirectedNeighbors tests that both dependencies and dependents are returned, each one once.
+func TestUndirectedNeighbors(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "lib", "X", "app")
+	dg.Add("lib", "util")
+	dg.Add("test", "lib")
+	dg.Add("util", "lib")
+
+	if res := dg.UndirectedNeighbors("lib"); !slices.Equal(res, []string{"util", "app", "test"}) {
+		t.Fatalf("neighbors computed incorrectly: %v", res)
+	}
+
+	if res := dg.UndirectedNeighbors("app"); !slices.Equal(res, []string{"lib"}) {
+		t.Fatalf("neighbors of a self-dependent node computed incorrectly: %v", res)
+	}
+
+	if res := dg.UndirectedNeighbors("X"); res != nil {
+		t.Fatalf("computed neighbors of an unknown node: %v", res)
+	}
+}
+
+// TestOrphansFromRepeated tests that repeating a root does not affect the orphans.
+func TestOrphansFromRepeated(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "B")
+	dg.Add("B")
+	dg.Add("C")
+
+	if res := dg.OrphansFrom("A", "A"); !slices.Equal(res, []string{"C"}) {
+		t.Fatalf("orphans from repeated roots found incorrectly: %v", res)
+	}
+}
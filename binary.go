@@ -0,0 +1,270 @@
+package depgraph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// binaryMagic prefixes every graph written by WriteBinary, identifying the format;
+// it is followed by a single byte holding the version of the format.
+var binaryMagic = []byte("DGB")
+
+// binaryVersion is the version of the format written by WriteBinary.
+// Version 2 flags the weak dependencies in the lowest bit of their indices; version 1, which did not,
+// is still accepted by ReadBinary.
+const binaryVersion = 2
+
+// errInvalidBinary is used when the binary input does not follow the format.
+var errInvalidBinary = errors.New("invalid binary graph")
+
+// WriteBinary writes the graph's structure into w using a compact binary format:
+// every node is encoded with enc and written once, as a varint-length-prefixed blob,
+// while the edges refer to the nodes by their varint-encoded indices, which also flag the weak dependencies.
+// Dependency costs and capabilities are not written.
+// The graph may be read back with ReadBinary, preserving the insertion order.
+func (dg *DependencyGraph[T]) WriteBinary(w io.Writer, enc func(T) []byte) error {
+	nodes, index := dg.indexNodes()
+
+	bw := bufio.NewWriter(w)
+	buf := append([]byte{}, binaryMagic...)
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(nodes)))
+	buf = binary.AppendUvarint(buf, uint64(len(dg.edges)))
+
+	for _, node := range nodes {
+		blob := enc(node)
+		buf = binary.AppendUvarint(buf, uint64(len(blob)))
+		buf = append(buf, blob...)
+
+		_, err := bw.Write(buf)
+		if err != nil {
+			return fmt.Errorf("writing binary graph: %w", err)
+		}
+
+		buf = buf[:0]
+	}
+
+	for _, edge := range dg.edges {
+		buf = binary.AppendUvarint(buf, uint64(len(edge.depOrder)))
+		for _, dep := range edge.depOrder {
+			flag := uint64(0)
+			if _, ok := edge.weak[dep]; ok {
+				flag = 1
+			}
+
+			buf = binary.AppendUvarint(buf, uint64(index[dep])<<1|flag)
+		}
+
+		_, err := bw.Write(buf)
+		if err != nil {
+			return fmt.Errorf("writing binary graph: %w", err)
+		}
+
+		buf = buf[:0]
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("writing binary graph: %w", err)
+	}
+
+	return nil
+}
+
+// ReadBinary reads a graph written by WriteBinary from r, decoding every node with dec,
+// and adds its elements to this graph in the original insertion order.
+// Since r is buffered internally, it may be read past the end of the graph.
+// Once read, the elements are validated, so that a broken input is reported right away
+// rather than on the first resolution: each of their dependencies has to be present in the graph,
+// unless it gets supplied by the node provider or accepted by the unknown dependency handler, just as with Validate.
+// Only the elements read and the ones supplied on their behalf are validated, so the problems,
+// which the graph has already had, such as the unknown dependencies of its other elements, do not get in the way;
+// the checks covering the whole graph, such as the one of WithRequireConnected, are left to the resolution,
+// and so are circular dependencies, unless ReadBinaryAcyclic is used.
+// Nothing is added to the graph if the input is malformed, cannot be decoded,
+// encodes the same node twice, or if any of the elements is invalid.
+func (dg *DependencyGraph[T]) ReadBinary(r io.Reader, dec func([]byte) (T, error)) error {
+	return dg.readBinaryChecked(r, dec, false)
+}
+
+// ReadBinaryAcyclic works like ReadBinary, but also checks that none of the elements,
+// which it has read, is a part of a circular dependency.
+func (dg *DependencyGraph[T]) ReadBinaryAcyclic(r io.Reader, dec func([]byte) (T, error)) error {
+	return dg.readBinaryChecked(r, dec, true)
+}
+
+// readBinaryChecked implements ReadBinary and ReadBinaryAcyclic.
+func (dg *DependencyGraph[T]) readBinaryChecked(r io.Reader, dec func([]byte) (T, error), acyclic bool) error {
+	// The elements are only added once the whole input has been read,
+	// but they are checked within the resulting graph, so they may have to be rolled back.
+	snap := dg.Snapshot()
+
+	read, err := dg.readBinary(bufio.NewReader(r), dec)
+	if err != nil {
+		return fmt.Errorf("reading binary graph: %w", err)
+	}
+
+	edges := make([]*depEdge[T], 0, len(read))
+	for _, name := range read {
+		edges = append(edges, dg.edgeMap[name])
+	}
+
+	err = dg.validateDeps(edges)
+	if err == nil && acyclic {
+		err = dg.checkAcyclic(read)
+	}
+
+	if err != nil {
+		dg.Restore(snap)
+		return fmt.Errorf("validating binary graph: %w", err)
+	}
+
+	return nil
+}
+
+// checkAcyclic checks that none of the given elements is a part of a circular dependency.
+func (dg *DependencyGraph[T]) checkAcyclic(names []T) error {
+	check := make(map[T]struct{}, len(names))
+	for _, name := range names {
+		check[name] = struct{}{}
+	}
+
+	comp := dg.stronglyConnected()
+
+	sizes := make([]int, len(comp))
+	for _, c := range comp {
+		sizes[c]++
+	}
+
+	for i, edge := range dg.edges {
+		if _, ok := check[edge.name]; !ok {
+			continue
+		}
+
+		if _, self := edge.deps[edge.name]; self || sizes[comp[i]] > 1 {
+			return fmt.Errorf("checking node \"%v\": %w", edge.name, ErrCircularDependency)
+		}
+	}
+
+	return nil
+}
+
+// readBinary implements ReadBinary, returning the elements that it has added.
+func (dg *DependencyGraph[T]) readBinary(br *bufio.Reader, dec func([]byte) (T, error)) ([]T, error) {
+	magic := make([]byte, len(binaryMagic)+1)
+
+	_, err := io.ReadFull(br, magic)
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+
+	version := magic[len(binaryMagic)]
+	if !bytes.Equal(magic[:len(binaryMagic)], binaryMagic) || version < 1 || version > binaryVersion {
+		return nil, fmt.Errorf("unexpected header %q: %w", magic, errInvalidBinary)
+	}
+
+	nodeCount, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading node count: %w", err)
+	}
+
+	edgeCount, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("reading edge count: %w", err)
+	}
+
+	if edgeCount > nodeCount {
+		return nil, fmt.Errorf("edge count %d exceeds node count %d: %w", edgeCount, nodeCount, errInvalidBinary)
+	}
+
+	// The counts are not trusted, so the memory is only allocated as the data arrives.
+	nodes := []T{}
+	seen := map[T]uint64{}
+	var blob bytes.Buffer
+
+	for i := range nodeCount {
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading size of node %d: %w", i, err)
+		}
+
+		if size > math.MaxInt64 {
+			return nil, fmt.Errorf("size of node %d is too large: %w", i, errInvalidBinary)
+		}
+
+		blob.Reset()
+
+		_, err = io.CopyN(&blob, br, int64(size))
+		if err != nil {
+			return nil, fmt.Errorf("reading node %d: %w", i, err)
+		}
+
+		node, err := dec(blob.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("decoding node %d: %w", i, err)
+		}
+
+		if j, ok := seen[node]; ok {
+			return nil, fmt.Errorf("node %d duplicates node %d: %w", i, j, errInvalidBinary)
+		}
+
+		seen[node] = i
+		nodes = append(nodes, node)
+	}
+
+	deps := make([][]T, 0, len(nodes))
+	weak := make([]depList[T], 0, len(nodes))
+
+	for i := range edgeCount {
+		depCount, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("reading dependency count of node %d: %w", i, err)
+		}
+
+		edgeDeps := []T{}
+		var edgeWeak depList[T]
+
+		for range depCount {
+			idx, err := binary.ReadUvarint(br)
+			if err != nil {
+				return nil, fmt.Errorf("reading dependency of node %d: %w", i, err)
+			}
+
+			isWeak := false
+			if version >= 2 {
+				idx, isWeak = idx>>1, idx&1 != 0
+			}
+
+			if idx >= nodeCount {
+				return nil, fmt.Errorf("dependency index %d of node %d is out of range: %w", idx, i, errInvalidBinary)
+			}
+
+			edgeDeps = append(edgeDeps, nodes[idx])
+
+			if isWeak {
+				if edgeWeak == nil {
+					edgeWeak = depList[T]{}
+				}
+
+				edgeWeak[nodes[idx]] = struct{}{}
+			}
+		}
+
+		deps = append(deps, edgeDeps)
+		weak = append(weak, edgeWeak)
+	}
+
+	read := make([]T, 0, len(deps))
+
+	for i, edgeDeps := range deps {
+		dg.addDeps(nodes[i], edgeDeps, weak[i])
+		read = append(read, dg.resolveAlias(nodes[i]))
+	}
+
+	return read, nil
+}
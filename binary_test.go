@@ -0,0 +1,272 @@
+package depgraph
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestBinaryRoundTrip tests that a graph survives being written and read back.
+func TestBinaryRoundTrip(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("B", "A", "X")
+	dg.Add("A")
+	dg.Add("C", "B", "A")
+	dg.Add("D")
+
+	var buf bytes.Buffer
+
+	err := dg.WriteBinary(&buf, func(s string) []byte { return []byte(s) })
+	if err != nil {
+		t.Fatalf("writing binary graph: %v", err)
+	}
+
+	// The unknown dependency survives the round trip, but the validation has to let it through.
+	read := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		return nil
+	}))
+
+	err = read.ReadBinary(&buf, func(b []byte) (string, error) { return string(b), nil })
+	if err != nil {
+		t.Fatalf("reading binary graph: %v", err)
+	}
+
+	if read.Hash(stringBytes) != dg.Hash(stringBytes) {
+		t.Fatalf("binary graph has been read incorrectly")
+	}
+
+	read.Add("X")
+	dg.Add("X")
+
+	res, err := read.Resolve()
+	if err != nil {
+		t.Fatalf("resolving binary graph: %v", err)
+	}
+
+	expected, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving original graph: %v", err)
+	}
+
+	if !slices.Equal(res, expected) {
+		t.Fatalf("binary graph resolved differently: %v; expected = %v", res, expected)
+	}
+}
+
+// TestBinaryMalformed tests that malformed binary input gets rejected without modifying the graph.
+func TestBinaryMalformed(t *testing.T) {
+	dec := func(b []byte) (string, error) {
+		if len(b) == 0 {
+			return "", errors.New("empty node")
+		}
+
+		return string(b), nil
+	}
+
+	tbl := []string{
+		"",
+		"XYZ\x01",
+		"DGB\x01\x01",
+		"DGB\x01\x01\x02",
+		"DGB\x01\x01\x01\x05AB",
+		"DGB\x01\x01\x01\x00\x00",
+		"DGB\x01\x01\x01\x01A\x01\x07",
+		"DGB\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01",
+		"DGB\x02",
+		"DGB\x02\x01\x01",
+		"DGB\x02\x01\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01",
+		"DGB\x02\x01\x01\x01A",
+		"DGB\x02\x01\x01\x01A\x01",
+		"DGB\x03\x00\x00",
+	}
+
+	for _, in := range tbl {
+		dg := NewDependencyGraph[string]()
+
+		err := dg.ReadBinary(strings.NewReader(in), dec)
+		if err == nil {
+			t.Fatalf("read malformed binary graph: %q", in)
+		}
+
+		if len(dg.edges) != 0 {
+			t.Fatalf("malformed binary graph has been partially read: %q", in)
+		}
+	}
+}
+
+// TestBinaryIntegrity tests that binary input, which decodes into a broken graph, gets rejected
+// without modifying the graph.
+func TestBinaryIntegrity(t *testing.T) {
+	enc := func(s string) []byte { return []byte(s) }
+	dec := func(b []byte) (string, error) { return strings.ToUpper(string(b)), nil }
+
+	// Nodes "a" and "A" decode into the same node.
+	src := NewDependencyGraph[string]()
+	src.Add("a")
+	src.Add("A")
+
+	var buf bytes.Buffer
+
+	err := src.WriteBinary(&buf, enc)
+	if err != nil {
+		t.Fatalf("writing binary graph: %v", err)
+	}
+
+	dg := NewDependencyGraph[string]()
+	dg.Add("B")
+
+	err = dg.ReadBinary(&buf, dec)
+	if !errors.Is(err, errInvalidBinary) {
+		t.Fatalf("read binary graph with duplicate nodes: %v", err)
+	}
+
+	src = NewDependencyGraph[string]()
+	src.Add("C", "X")
+	buf.Reset()
+
+	err = src.WriteBinary(&buf, enc)
+	if err != nil {
+		t.Fatalf("writing binary graph: %v", err)
+	}
+
+	err = dg.ReadBinary(&buf, dec)
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("read binary graph with unknown dependency: %v", err)
+	}
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"B"}) {
+		t.Fatalf("invalid binary graph has been partially read: %v", res)
+	}
+
+	dg.Add("C", "X")
+
+	// The unknown dependency of "C", which is already in the graph, does not get in the way.
+	src = NewDependencyGraph[string]()
+	src.Add("D", "B")
+	src.Add("B", "E")
+	src.Add("E", "D")
+	buf.Reset()
+
+	err = src.WriteBinary(&buf, enc)
+	if err != nil {
+		t.Fatalf("writing binary graph: %v", err)
+	}
+
+	err = dg.ReadBinaryAcyclic(bytes.NewReader(buf.Bytes()), dec)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("read binary graph with circular dependency: %v", err)
+	}
+
+	if _, ok := dg.edgeMap["D"]; ok || len(dg.edges) != 2 || len(dg.edgeMap["B"].deps) != 0 {
+		t.Fatalf("cyclic binary graph has been partially read")
+	}
+
+	err = dg.ReadBinary(bytes.NewReader(buf.Bytes()), dec)
+	if err != nil {
+		t.Fatalf("reading binary graph with circular dependency, which is not checked: %v", err)
+	}
+
+	if _, ok := dg.edgeMap["E"]; !ok {
+		t.Fatalf("binary graph has not been read")
+	}
+
+	// The cycle, which is already in the graph, does not get in the way either.
+	src = NewDependencyGraph[string]()
+	src.Add("F", "C")
+	buf.Reset()
+
+	err = src.WriteBinary(&buf, enc)
+	if err != nil {
+		t.Fatalf("writing binary graph: %v", err)
+	}
+
+	err = dg.ReadBinaryAcyclic(&buf, dec)
+	if err != nil {
+		t.Fatalf("reading acyclic binary graph into a cyclic one: %v", err)
+	}
+}
+
+// TestBinaryWeak tests that weak dependencies survive being written and read back,
+// and that the graphs written before they have been flagged can still be read.
+func TestBinaryWeak(t *testing.T) {
+	enc := func(s string) []byte { return []byte(s) }
+	dec := func(b []byte) (string, error) { return string(b), nil }
+
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "B")
+	dg.AddWeak("A", "X")
+	dg.Add("B")
+
+	var buf bytes.Buffer
+
+	err := dg.WriteBinary(&buf, enc)
+	if err != nil {
+		t.Fatalf("writing binary graph: %v", err)
+	}
+
+	read := NewDependencyGraph[string]()
+
+	err = read.ReadBinary(&buf, dec)
+	if err != nil {
+		t.Fatalf("reading binary graph with weak dependencies: %v", err)
+	}
+
+	if !read.isWeak("A", "X") || read.isWeak("A", "B") {
+		t.Fatalf("weak dependencies have been read incorrectly: %v", read.edgeMap["A"].weak)
+	}
+
+	if res := read.MustResolve(); !slices.Equal(res, []string{"B", "A"}) {
+		t.Fatalf("binary graph with weak dependencies resolved incorrectly: %v", res)
+	}
+
+	// Version 1: node "A", which depends on node "B", and node "B".
+	read = NewDependencyGraph[string]()
+
+	err = read.ReadBinary(strings.NewReader("DGB\x01\x02\x02\x01A\x01B\x01\x01\x00"), dec)
+	if err != nil {
+		t.Fatalf("reading version 1 binary graph: %v", err)
+	}
+
+	if res := read.MustResolve(); !slices.Equal(res, []string{"B", "A"}) {
+		t.Fatalf("version 1 binary graph resolved incorrectly: %v", res)
+	}
+}
+
+// TestBinaryWriteFailures tests that the errors of the underlying writer are reported,
+// whether they occur while writing the nodes, the edges, or the buffered rest of the graph.
+func TestBinaryWriteFailures(t *testing.T) {
+	// A long node, which does not fit into the write buffer, fails right away.
+	dg := NewDependencyGraph[string]()
+	dg.Add(strings.Repeat("A", 8192))
+
+	err := dg.WriteBinary(&lineWriter{}, func(s string) []byte { return []byte(s) })
+	if err == nil {
+		t.Fatalf("writer error not reported when writing nodes")
+	}
+
+	// So does a long dependency list, since the nodes themselves encode into nothing.
+	ints := NewDependencyGraph[int]()
+	for i := range 2000 {
+		ints.Add(0, i+1)
+	}
+
+	err = ints.WriteBinary(&lineWriter{}, func(int) []byte { return nil })
+	if err == nil {
+		t.Fatalf("writer error not reported when writing edges")
+	}
+
+	dg = NewDependencyGraph[string]()
+	dg.Add("A")
+
+	err = dg.WriteBinary(&lineWriter{}, func(s string) []byte { return []byte(s) })
+	if err == nil {
+		t.Fatalf("writer error not reported when flushing graph")
+	}
+}
+
+// stringBytes encodes a string for hashing.
+func stringBytes(s string) []byte {
+	return []byte(s)
+}
@@ -0,0 +1,115 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AddProvider adds an element, just like Add does, and declares that it provides the given capabilities.
+// Every element, which requires any of these capabilities, gets wired to depend on this element.
+// An element never depends on itself for a capability it provides.
+func (dg *DependencyGraph[T]) AddProvider(name T, provides ...string) {
+	name = dg.resolveAlias(name)
+	dg.Add(name)
+
+	edge := dg.edgeMap[name]
+	for _, capability := range provides {
+		if !slices.Contains(edge.provides, capability) {
+			edge.provides = append(edge.provides, capability)
+		}
+	}
+
+	for _, consumer := range dg.edges {
+		if consumer != edge && hasCommonCapability(consumer.requires, provides) {
+			dg.Add(consumer.name, name)
+		}
+	}
+}
+
+// AddConsumer adds an element, just like Add does, and declares that it requires the given capabilities.
+// The element gets wired to depend on every element, which provides any of these capabilities,
+// including the providers added later on.
+// If a capability has several providers, the element depends on all of them;
+// such ambiguities may be reported by AmbiguousCapabilities.
+// Requiring a capability, which is not provided by any element, fails the validation with ErrUnknownCapability.
+func (dg *DependencyGraph[T]) AddConsumer(name T, requires ...string) {
+	name = dg.resolveAlias(name)
+	dg.Add(name)
+
+	edge := dg.edgeMap[name]
+	for _, capability := range requires {
+		if !slices.Contains(edge.requires, capability) {
+			edge.requires = append(edge.requires, capability)
+		}
+	}
+
+	providers := []T{}
+	for _, provider := range dg.edges {
+		if provider != edge && hasCommonCapability(provider.provides, requires) {
+			providers = append(providers, provider.name)
+		}
+	}
+
+	dg.Add(name, providers...)
+}
+
+// AmbiguousCapabilities returns the capabilities, which are provided by more than one element,
+// mapped to their providers in the edge list order.
+func (dg *DependencyGraph[T]) AmbiguousCapabilities() map[string][]T {
+	providers := dg.providers()
+
+	for capability, list := range providers {
+		if len(list) < 2 {
+			delete(providers, capability)
+		}
+	}
+
+	return providers
+}
+
+// providers maps every provided capability to its providers, in the edge list order.
+func (dg *DependencyGraph[T]) providers() map[string][]T {
+	res := map[string][]T{}
+
+	for _, edge := range dg.edges {
+		for _, capability := range edge.provides {
+			res[capability] = append(res[capability], edge.name)
+		}
+	}
+
+	return res
+}
+
+// validateCapabilities checks that every required capability has at least one provider.
+func (dg *DependencyGraph[T]) validateCapabilities() error {
+	var providers map[string][]T
+
+	for _, edge := range dg.edges {
+		if len(edge.requires) == 0 {
+			continue
+		}
+
+		if providers == nil {
+			providers = dg.providers()
+		}
+
+		for _, capability := range edge.requires {
+			if _, ok := providers[capability]; !ok {
+				return fmt.Errorf("looking up capability \"%s\" required by \"%v\": %w", capability, edge.name, ErrUnknownCapability)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasCommonCapability reports whether two capability lists intersect.
+func hasCommonCapability(a, b []string) bool {
+	for _, capability := range a {
+		if slices.Contains(b, capability) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package depgraph
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestCapabilities tests that consumers get wired to the providers of their capabilities,
+// regardless of which of them has been added first.
+func TestCapabilities(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.AddConsumer("app", "db", "cache")
+	dg.AddProvider("postgres", "db")
+	dg.AddProvider("redis", "cache")
+	dg.AddConsumer("worker", "db")
+	dg.AddProvider("memcached", "cache", "memcached")
+	dg.AddConsumer("memcached", "memcached")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with capabilities: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"postgres", "redis", "memcached", "worker", "app"}) {
+		t.Fatalf("graph with capabilities resolved incorrectly: %v", res)
+	}
+
+	ambiguous := dg.AmbiguousCapabilities()
+	if !maps.EqualFunc(ambiguous, map[string][]string{"cache": {"redis", "memcached"}}, slices.Equal) {
+		t.Fatalf("ambiguous capabilities reported incorrectly: %v", ambiguous)
+	}
+
+	dg.AddConsumer("cron", "scheduler")
+
+	_, err = dg.Resolve()
+	if !errors.Is(err, ErrUnknownCapability) {
+		t.Fatalf("resolved graph with unknown capability: %v", err)
+	}
+}
+
+// TestCapabilitiesAlias tests that providers and consumers may be referred to by their aliases.
+func TestCapabilitiesAlias(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("db")
+	dg.Add("app")
+
+	for _, alias := range [][2]string{{"db", "database"}, {"app", "application"}} {
+		err := dg.Alias(alias[0], alias[1])
+		if err != nil {
+			t.Fatalf("adding alias: %v", err)
+		}
+	}
+
+	dg.AddProvider("database", "storage")
+	dg.AddConsumer("application", "storage")
+	dg.AddConsumer("worker", "storage")
+
+	for _, node := range []string{"app", "worker"} {
+		if deps := dg.Dependencies(node); !slices.Equal(deps, []string{"db"}) {
+			t.Fatalf("consumer %v wired incorrectly: %v", node, deps)
+		}
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with aliased capabilities: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"db", "app", "worker"}) {
+		t.Fatalf("graph with aliased capabilities resolved incorrectly: %v", res)
+	}
+}
+
+// TestCapabilitiesSubgraphs tests that resolving a part of the graph does not require
+// the providers, which have been left out, while the graph itself is still checked.
+func TestCapabilitiesSubgraphs(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.AddProvider("db", "sql")
+	dg.AddConsumer("svc", "sql")
+
+	tbl := []struct {
+		name    string
+		resolve func() ([]string, error)
+	}{
+		{name: "RebuildOrder", resolve: func() ([]string, error) { return dg.RebuildOrder("db") }},
+		{name: "ResolveFrom", resolve: func() ([]string, error) { return dg.ResolveFrom([]string{"db"}) }},
+		{name: "ResolveAgainst", resolve: func() ([]string, error) {
+			return dg.ResolveAgainst(func(name string) bool { return name == "db" })
+		}},
+		{name: "ResolveExcluding", resolve: func() ([]string, error) { return dg.ResolveExcluding("db") }},
+	}
+
+	for _, test := range tbl {
+		res, err := test.resolve()
+		if err != nil {
+			t.Fatalf("%s: resolving part of graph with capabilities: %v", test.name, err)
+		}
+
+		if !slices.Equal(res, []string{"svc"}) {
+			t.Fatalf("%s: part of graph with capabilities resolved incorrectly: %v", test.name, res)
+		}
+	}
+
+	dg.AddConsumer("cron", "scheduler")
+
+	_, err := dg.ResolveFrom([]string{"db"})
+	if !errors.Is(err, ErrUnknownCapability) {
+		t.Fatalf("resolved graph with unknown capability from prefix: %v", err)
+	}
+}
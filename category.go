@@ -0,0 +1,27 @@
+package depgraph
+
+// categoryRank returns the position of an element's category in the category order;
+// categories, which are not listed in the order, go after all the listed ones.
+func (dg *DependencyGraph[T]) categoryRank(name T) int {
+	if rank, ok := dg.categoryRanks[dg.categoryOf(name)]; ok {
+		return rank
+	}
+
+	return len(dg.categoryRanks)
+}
+
+// pickCategory moves the first edge of the free list, whose category goes first in the category order,
+// to its front, shifting the preceding edges to keep their relative order.
+func (dg *DependencyGraph[T]) pickCategory(free []*depEdge[T]) {
+	best, bestRank := 0, dg.categoryRank(free[0].name)
+
+	for i := 1; i < len(free) && bestRank > 0; i++ {
+		if rank := dg.categoryRank(free[i].name); rank < bestRank {
+			best, bestRank = i, rank
+		}
+	}
+
+	edge := free[best]
+	copy(free[1:best+1], free[:best])
+	free[0] = edge
+}
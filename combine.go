@@ -0,0 +1,112 @@
+package depgraph
+
+import "slices"
+
+// ResolveAll resolves several graphs together, as if they were a single graph containing
+// the union of their nodes and edges, and returns a single resolution order.
+// The nodes follow the order of the graphs, then the insertion order within each graph;
+// a node present in several graphs is placed according to its first occurrence,
+// and its dependencies are concatenated together; a dependency stays weak only if it is weak
+// in every graph, which has it.
+// Any options of the graphs are not taken into account.
+// Since a dependency may be satisfied by another graph, combining the graphs may both
+// resolve unknown dependencies and introduce circular dependencies spanning across the graphs.
+// None of the graphs are modified.
+func ResolveAll[T comparable](graphs ...*DependencyGraph[T]) ([]T, error) {
+	size := 0
+	for _, g := range graphs {
+		size += len(g.edges)
+	}
+
+	combined := NewDependencyGraphWithCapacity[T](size)
+
+	for _, g := range graphs {
+		for _, edge := range g.edges {
+			combined.addDeps(edge.name, edge.depOrder, edge.weak)
+		}
+	}
+
+	return combined.Resolve()
+}
+
+// MergeWith merges another graph into this one: the nodes, which are only present in the other graph,
+// are appended in its edge list order, along with their dependencies.
+// When both graphs define the same node, onConflict receives the node's dependencies in this graph
+// and in the other one, in the order in which they have been added, and returns the merged dependencies,
+// which replace the node's current ones; the node keeps its position in the edge list.
+// If onConflict is nil, the dependencies are concatenated together, just like Add does.
+// Either way, a dependency stays weak only if it is weak in both graphs, or in the only graph, which has it;
+// the dependencies introduced by onConflict are regular.
+// Dependency costs and capabilities of the other graph are not merged. The other graph is not modified.
+func (dg *DependencyGraph[T]) MergeWith(other *DependencyGraph[T], onConflict func(node T, a, b []T) []T) {
+	dg.mutate()
+
+	for _, edge := range other.edges {
+		name := dg.resolveAlias(edge.name)
+
+		existing, ok := dg.edgeMap[name]
+		if !ok || onConflict == nil {
+			dg.addDeps(name, edge.depOrder, edge.weak)
+			continue
+		}
+
+		merged := onConflict(name, slices.Clone(existing.depOrder), slices.Clone(edge.depOrder))
+		if len(dg.aliases) > 0 {
+			_, merged = dg.canonicalize(name, merged)
+		}
+
+		deps := make(depList[T], len(merged))
+		order := make([]T, 0, len(merged))
+
+		for _, dep := range merged {
+			if _, ok := deps[dep]; !ok {
+				deps[dep] = struct{}{}
+				order = append(order, dep)
+			}
+		}
+
+		// A merged dependency stays weak only if it is weak in every graph, which has it.
+		weak := depList[T]{}
+		regular := depList[T]{}
+
+		for dep := range existing.deps {
+			if _, ok := existing.weak[dep]; ok {
+				weak[dep] = struct{}{}
+			} else {
+				regular[dep] = struct{}{}
+			}
+		}
+
+		for _, dep := range edge.depOrder {
+			if _, ok := edge.weak[dep]; ok {
+				weak[dg.resolveAlias(dep)] = struct{}{}
+			} else {
+				regular[dg.resolveAlias(dep)] = struct{}{}
+			}
+		}
+
+		existing.weak = nil
+
+		for _, dep := range order {
+			_, isWeak := weak[dep]
+			_, isRegular := regular[dep]
+
+			if isWeak && !isRegular {
+				if existing.weak == nil {
+					existing.weak = depList[T]{}
+				}
+
+				existing.weak[dep] = struct{}{}
+			}
+		}
+
+		for dep := range existing.costs {
+			if _, ok := deps[dep]; !ok {
+				delete(existing.costs, dep)
+			}
+		}
+
+		existing.deps = deps
+		existing.depOrder = order
+	}
+}
@@ -0,0 +1,199 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestResolveAll tests that several graphs get resolved as a single combined graph.
+func TestResolveAll(t *testing.T) {
+	a := NewDependencyGraph[string]()
+	a.Add("A")
+	a.Add("B", "A", "D")
+
+	b := NewDependencyGraph[string]()
+	b.Add("C", "B")
+	b.Add("D")
+	b.Add("A", "D")
+
+	res, err := ResolveAll(a, b)
+	if err != nil {
+		t.Fatalf("resolving combined graphs: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"D", "A", "B", "C"}) {
+		t.Fatalf("combined graphs resolved incorrectly: %v", res)
+	}
+
+	_, err = a.Resolve()
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("resolved graph with unknown dependency after combining: %v", err)
+	}
+
+	b.Add("D", "C")
+
+	_, err = ResolveAll(a, b)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved combined graphs with cross-graph cycle: %v", err)
+	}
+
+	res, err = ResolveAll[string]()
+	if err != nil || len(res) != 0 {
+		t.Fatalf("resolving no graphs: %v, %v", res, err)
+	}
+}
+
+// TestMergeWith tests that the conflicting definitions get merged by the callback,
+// and that the non-conflicting ones get added.
+func TestMergeWith(t *testing.T) {
+	a := NewDependencyGraph[string]()
+	a.Add("app", "db", "cache")
+	a.Add("db")
+	a.Add("cache")
+
+	b := NewDependencyGraph[string]()
+	b.Add("app", "db", "queue")
+	b.Add("queue")
+	b.Add("db")
+
+	conflicts := []string{}
+
+	a.MergeWith(b, func(node string, x, y []string) []string {
+		conflicts = append(conflicts, node)
+
+		// Keep the dependencies both graphs agree on.
+		return slices.DeleteFunc(x, func(dep string) bool {
+			return !slices.Contains(y, dep)
+		})
+	})
+
+	if !slices.Equal(conflicts, []string{"app", "db"}) {
+		t.Fatalf("conflicts reported incorrectly: %v", conflicts)
+	}
+
+	if deps := a.Dependencies("app"); !slices.Equal(deps, []string{"db"}) {
+		t.Fatalf("conflicting dependencies merged incorrectly: %v", deps)
+	}
+
+	if res := a.MustResolve(); !slices.Equal(res, []string{"db", "cache", "queue", "app"}) {
+		t.Fatalf("merged graph resolved incorrectly: %v", res)
+	}
+
+	a.MergeWith(b, nil)
+
+	if deps := a.Dependencies("app"); !slices.Equal(deps, []string{"db", "queue"}) {
+		t.Fatalf("dependencies merged incorrectly by default: %v", deps)
+	}
+
+	if deps := b.Dependencies("app"); !slices.Equal(deps, []string{"db", "queue"}) {
+		t.Fatalf("other graph modified by merging: %v", deps)
+	}
+}
+
+// TestResolveAllWeak tests that weak dependencies stay weak once the graphs are combined.
+func TestResolveAllWeak(t *testing.T) {
+	a := NewDependencyGraph[string]()
+	a.AddWeak("A", "X")
+	a.AddWeak("A", "B")
+
+	b := NewDependencyGraph[string]()
+	b.Add("B")
+
+	res, err := ResolveAll(a, b)
+	if err != nil {
+		t.Fatalf("resolving combined graphs with weak dependencies: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"B", "A"}) {
+		t.Fatalf("combined graphs with weak dependencies resolved incorrectly: %v", res)
+	}
+
+	b.Add("A", "X")
+
+	_, err = ResolveAll(a, b)
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("dependency, which is regular in one of the graphs, stayed weak: %v", err)
+	}
+}
+
+// TestMergeWithWeak tests that weak dependencies stay weak once merged,
+// both with and without the conflict callback.
+func TestMergeWithWeak(t *testing.T) {
+	a := NewDependencyGraph[string]()
+	a.Add("A")
+	a.AddWeak("B", "X")
+	a.AddWeak("B", "Y")
+
+	b := NewDependencyGraph[string]()
+	b.AddWeak("C", "Z")
+	b.Add("B", "Y")
+	b.AddWeak("B", "W")
+
+	a.MergeWith(b, func(_ string, x, y []string) []string {
+		return append(append(x, y...), "A")
+	})
+
+	if !a.isWeak("C", "Z") {
+		t.Fatalf("weak dependency of a new node has become regular")
+	}
+
+	if !a.isWeak("B", "X") || !a.isWeak("B", "W") || a.isWeak("B", "Y") || a.isWeak("B", "A") {
+		t.Fatalf("weak dependencies of a conflicting node merged incorrectly: %v", a.edgeMap["B"].weak)
+	}
+
+	a.Add("Y")
+
+	res, err := a.Resolve()
+	if err != nil {
+		t.Fatalf("resolving merged graph with weak dependencies: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "C", "Y", "B"}) {
+		t.Fatalf("merged graph with weak dependencies resolved incorrectly: %v", res)
+	}
+
+	c := NewDependencyGraph[string]()
+	c.Add("B", "X")
+	c.AddWeak("B", "V")
+
+	a.MergeWith(c, nil)
+
+	if a.isWeak("B", "X") || !a.isWeak("B", "V") {
+		t.Fatalf("weak dependencies of a concatenated node merged incorrectly: %v", a.edgeMap["B"].weak)
+	}
+}
+
+// TestMergeWithAliasCosts tests that the merged dependencies get resolved through aliases,
+// and that the costs of the dropped dependencies get dropped along with them.
+func TestMergeWithAliasCosts(t *testing.T) {
+	a := NewDependencyGraph[string]()
+	a.Add("db")
+	a.Add("cache")
+	a.AddCost("app", "db", 2)
+	a.AddCost("app", "cache", 3)
+
+	err := a.Alias("db", "database")
+	if err != nil {
+		t.Fatalf("aliasing node: %v", err)
+	}
+
+	b := NewDependencyGraph[string]()
+	b.Add("app", "database")
+
+	a.MergeWith(b, func(_ string, _, y []string) []string {
+		return y
+	})
+
+	if deps := a.Dependencies("app"); !slices.Equal(deps, []string{"db"}) {
+		t.Fatalf("aliased dependencies merged incorrectly: %v", deps)
+	}
+
+	if _, ok := a.edgeMap["app"].costs["cache"]; ok {
+		t.Fatalf("cost of a dropped dependency retained")
+	}
+
+	if _, cost, ok := a.CheapestPath("app", "db"); !ok || cost != 2 {
+		t.Fatalf("cost of a kept dependency merged incorrectly: %v, %v", cost, ok)
+	}
+}
@@ -0,0 +1,398 @@
+package depgraph
+
+// FeedbackNodeSet returns a set of nodes, whose removal makes the graph acyclic.
+// For acyclic graphs, the returned set is empty.
+//
+// Finding the minimum feedback node set is NP-hard, therefore a greedy heuristic is used instead:
+// nodes that cannot be a part of any cycle (the ones without either dependencies or dependents)
+// are repeatedly peeled off the graph, and when there are none left, the node with the highest
+// product of its dependency and dependent counts is removed and added to the set.
+// Ties are broken by the insertion order.
+// The resulting set is not guaranteed to be minimal, but it usually comes close.
+//
+// Unknown dependencies are ignored. The nodes are returned in the edge list order.
+func (dg *DependencyGraph[T]) FeedbackNodeSet() []T {
+	outdeg, dependents := dg.adjacency()
+	deps := dg.depIndices()
+
+	n := len(dg.edges)
+	alive := make([]bool, n)
+	indeg := make([]int, n)
+	inSet := make([]bool, n)
+
+	for i := range n {
+		alive[i] = true
+		indeg[i] = len(dependents[i])
+	}
+
+	remove := func(i int) {
+		alive[i] = false
+
+		for _, j := range deps[i] {
+			indeg[j]--
+		}
+
+		for _, k := range dependents[i] {
+			outdeg[k]--
+		}
+	}
+
+	remaining := n
+	queue := make([]int, 0, n)
+
+	for remaining > 0 {
+		// Peel off all nodes which cannot participate in a cycle.
+		queue = queue[:0]
+		for i := range n {
+			if alive[i] && (indeg[i] == 0 || outdeg[i] == 0) {
+				queue = append(queue, i)
+			}
+		}
+
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+
+			if !alive[i] {
+				continue
+			}
+
+			remove(i)
+			remaining--
+
+			for _, j := range deps[i] {
+				if alive[j] && indeg[j] == 0 {
+					queue = append(queue, j)
+				}
+			}
+
+			for _, k := range dependents[i] {
+				if alive[k] && outdeg[k] == 0 {
+					queue = append(queue, k)
+				}
+			}
+		}
+
+		if remaining == 0 {
+			break
+		}
+
+		// Every remaining node lies on a cycle or between cycles: remove the most connected one.
+		best, bestScore := -1, -1
+		for i := range n {
+			if alive[i] && indeg[i]*outdeg[i] > bestScore {
+				best, bestScore = i, indeg[i]*outdeg[i]
+			}
+		}
+
+		inSet[best] = true
+		remove(best)
+		remaining--
+	}
+
+	res := []T{}
+	for i, edge := range dg.edges {
+		if inSet[i] {
+			res = append(res, edge.name)
+		}
+	}
+
+	return res
+}
+
+// WouldCycle reports whether adding dep as a dependency of name would introduce a circular dependency,
+// that is, whether name is already reachable from dep by following the dependencies.
+// Adding a node as its own dependency always creates a cycle.
+// The graph is not modified.
+// If the graph has been created with WithReachabilityCache, it takes a constant time.
+func (dg *DependencyGraph[T]) WouldCycle(name, dep T) bool {
+	return dg.reaches(dep, name)
+}
+
+// NearCycles returns the dependencies, which are a single inverted edge away from forming a cycle:
+// the pairs (A, B), such that A directly depends on B, and B leads back to A within maxHops edges,
+// if exactly one of the edges is followed against its direction.
+// In other words, inverting that one dependency, which is not the A -> B dependency itself,
+// would close a cycle of at most maxHops+1 edges; diamonds, where two paths merge again, are not reported.
+// Dependencies, which are already a part of a cycle, are not reported either.
+// Unknown dependencies are ignored.
+// The pairs follow the edge list order, and then the order in which the dependencies have been added.
+func (dg *DependencyGraph[T]) NearCycles(maxHops int) [][2]T {
+	_, dependents := dg.adjacency()
+	deps := dg.depIndices()
+
+	n := len(dg.edges)
+	res := [][2]T{}
+
+	// The search state is a node along with whether the inverted edge has been used already;
+	// it is encoded as 2*node+inverted, and stamped with the edge being checked.
+	visited := make([]int, 2*n)
+	stamp := 0
+
+	for a := range n {
+		for _, b := range deps[a] {
+			if a == b || dg.reaches(dg.edges[b].name, dg.edges[a].name) {
+				continue
+			}
+
+			stamp++
+			visited[2*b] = stamp
+			level := []int{2 * b}
+			found := false
+
+			for hop := 0; hop < maxHops && !found && len(level) > 0; hop++ {
+				next := []int{}
+
+				visit := func(state int) {
+					if visited[state] != stamp {
+						visited[state] = stamp
+						next = append(next, state)
+					}
+				}
+
+				for _, state := range level {
+					cur, inverted := state/2, state%2 == 1
+
+					for _, d := range deps[cur] {
+						visit(2*d + state%2)
+					}
+
+					if inverted {
+						continue
+					}
+
+					for _, d := range dependents[cur] {
+						if cur == b && d == a {
+							continue
+						}
+
+						visit(2*d + 1)
+					}
+				}
+
+				found = visited[2*a+1] == stamp
+				level = next
+			}
+
+			if found {
+				res = append(res, [2]T{dg.edges[a].name, dg.edges[b].name})
+			}
+		}
+	}
+
+	return res
+}
+
+// reaches reports whether to is reachable from from by following the dependencies,
+// or whether they are the same node.
+// If the graph has been created with WithReachabilityCache, the cached closure is consulted instead;
+// otherwise, the traversal uses an explicit stack, so that deep chains do not exhaust the call stack.
+func (dg *DependencyGraph[T]) reaches(from, to T) bool {
+	if from == to {
+		return true
+	}
+
+	if dg.cacheReach && dg.reach == nil && !dg.frozen {
+		dg.reach = dg.buildReach()
+	}
+
+	if dg.reach != nil {
+		return dg.reach.reaches(from, to)
+	}
+
+	visited := map[T]struct{}{from: {}}
+	stack := []T{from}
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		edge, ok := dg.edgeMap[cur]
+		if !ok {
+			continue
+		}
+
+		for _, dep := range edge.depOrder {
+			if dep == to {
+				return true
+			}
+
+			if _, ok := visited[dep]; !ok {
+				visited[dep] = struct{}{}
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	return false
+}
+
+// Condensation collapses every strongly connected component of the graph, meaning every group of nodes
+// which all depend on each other, either directly or transitively, into a single super-node.
+// It returns the condensation, which is always acyclic, along with the members of each super-node.
+// The super-nodes are numbered from zero in the order of their first member in the edge list,
+// and keep the dependencies between their members; unknown dependencies are ignored.
+// The members of each super-node follow the edge list order.
+func (dg *DependencyGraph[T]) Condensation() (*DependencyGraph[int], map[int][]T) {
+	comp := dg.stronglyConnected()
+
+	// Renumber the components in the order of their first member.
+	ids := make(map[int]int, len(comp))
+	for _, c := range comp {
+		if _, ok := ids[c]; !ok {
+			ids[c] = len(ids)
+		}
+	}
+
+	members := make(map[int][]T, len(ids))
+	for i, c := range comp {
+		members[ids[c]] = append(members[ids[c]], dg.edges[i].name)
+	}
+
+	cg := NewDependencyGraphWithCapacity[int](len(ids))
+	deps := dg.depIndices()
+
+	for i, c := range comp {
+		id := ids[c]
+		cg.Add(id)
+
+		for _, d := range deps[i] {
+			if dep := ids[comp[d]]; dep != id {
+				cg.Add(id, dep)
+			}
+		}
+	}
+
+	return cg, members
+}
+
+// componentClosures computes, for every strongly connected component, as numbered by stronglyConnected,
+// the bitset of the other components, which it transitively depends on; unknown dependencies are ignored.
+// The closures are assembled in dependency order, each one from the already completed closures
+// of its dependencies, rather than by a separate traversal from every component.
+// It also returns the component of every edge, and the edges of every component in the edge list order.
+func (dg *DependencyGraph[T]) componentClosures() ([]int, [][]int, [][]uint64) {
+	comp := dg.stronglyConnected()
+	deps := dg.depIndices()
+
+	comps := 0
+	for _, c := range comp {
+		comps = max(comps, c+1)
+	}
+
+	members := make([][]int, comps)
+	for i, c := range comp {
+		members[c] = append(members[c], i)
+	}
+
+	words := (comps + 63) / 64
+	closures := make([][]uint64, comps)
+
+	for c := range comps {
+		closure := make([]uint64, words)
+
+		for _, i := range members[c] {
+			for _, d := range deps[i] {
+				dc := comp[d]
+				if dc == c {
+					continue
+				}
+
+				closure[dc/64] |= 1 << (dc % 64)
+
+				for w, set := range closures[dc] {
+					closure[w] |= set
+				}
+			}
+		}
+
+		closures[c] = closure
+	}
+
+	return comp, members, closures
+}
+
+// stronglyConnected assigns every edge to its strongly connected component,
+// using an iterative version of the Tarjan's algorithm, so that deep chains do not exhaust the call stack.
+// Edges are referred to by their position in the edge list.
+// Components are numbered in the order of completion, so a component only depends on
+// the components with lower numbers, apart from itself.
+func (dg *DependencyGraph[T]) stronglyConnected() []int {
+	deps := dg.depIndices()
+
+	n := len(dg.edges)
+	index := make([]int, n)
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	comp := make([]int, n)
+
+	for i := range index {
+		index[i] = -1
+	}
+
+	type frame struct {
+		node, next int
+	}
+
+	counter, comps := 0, 0
+	stack := []int{}
+	frames := []frame{}
+
+	visit := func(v int) {
+		index[v], low[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		frames = append(frames, frame{node: v})
+	}
+
+	for s := range n {
+		if index[s] != -1 {
+			continue
+		}
+
+		visit(s)
+
+		for len(frames) > 0 {
+			f := &frames[len(frames)-1]
+			v := f.node
+
+			if f.next < len(deps[v]) {
+				w := deps[v][f.next]
+				f.next++
+
+				if index[w] == -1 {
+					visit(w)
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+
+			if low[v] == index[v] {
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					comp[w] = comps
+
+					if w == v {
+						break
+					}
+				}
+
+				comps++
+			}
+
+			if len(frames) > 0 {
+				parent := frames[len(frames)-1].node
+				low[parent] = min(low[parent], low[v])
+			}
+		}
+	}
+
+	return comp
+}
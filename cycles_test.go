@@ -0,0 +1,204 @@
+package depgraph
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestFeedbackNodeSet tests that the feedback node set heuristic
+// finds the obvious minimal sets.
+func TestFeedbackNodeSet(t *testing.T) {
+	tbl := []struct {
+		in  [][]string // [0]: element; [1:]: element's dependencies
+		out []string
+	}{
+		{
+			in:  [][]string{},
+			out: []string{},
+		},
+		{
+			in:  [][]string{{"A"}, {"B", "A"}, {"C", "B"}},
+			out: []string{},
+		},
+		{
+			in:  [][]string{{"A", "A"}, {"B"}},
+			out: []string{"A"},
+		},
+		{
+			in:  [][]string{{"A", "B"}, {"B", "A"}, {"C", "A"}},
+			out: []string{"A"},
+		},
+		{
+			// Two cycles sharing the node C.
+			in:  [][]string{{"A", "B"}, {"B", "C"}, {"C", "A", "D"}, {"D", "E"}, {"E", "C"}, {"F", "E"}},
+			out: []string{"C"},
+		},
+		{
+			// Two independent cycles.
+			in:  [][]string{{"A", "B"}, {"B", "A"}, {"C", "D"}, {"D", "C"}, {"E", "X"}},
+			out: []string{"A", "C"},
+		},
+	}
+
+	for _, test := range tbl {
+		dg := NewDependencyGraph[string]()
+
+		for _, in := range test.in {
+			dg.Add(in[0], in[1:]...)
+		}
+
+		res := dg.FeedbackNodeSet()
+		if !slices.Equal(res, test.out) {
+			t.Fatalf("feedback node set computed incorrectly: input = %v; output = %v; expected = %v", test.in, res, test.out)
+		}
+	}
+}
+
+// TestWouldCycle tests that adding an edge is reported as cycle-inducing
+// only when it would actually create a cycle.
+func TestWouldCycle(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "B")
+	dg.Add("D", "A", "X")
+
+	tbl := []struct {
+		name, dep string
+		cycle     bool
+	}{
+		{name: "A", dep: "A", cycle: true},
+		{name: "A", dep: "B", cycle: true},
+		{name: "A", dep: "C", cycle: true},
+		{name: "B", dep: "C", cycle: true},
+		{name: "C", dep: "A", cycle: false},
+		{name: "A", dep: "D", cycle: true},
+		{name: "B", dep: "D", cycle: false},
+		{name: "X", dep: "D", cycle: true},
+		{name: "Y", dep: "A", cycle: false},
+		{name: "A", dep: "Y", cycle: false},
+	}
+
+	for _, test := range tbl {
+		if dg.WouldCycle(test.name, test.dep) != test.cycle {
+			t.Fatalf("cycle check for edge %v -> %v is incorrect; expected %v", test.name, test.dep, test.cycle)
+		}
+	}
+}
+
+// TestDeepChain tests that traversals over a very deep linear chain do not exhaust the call stack,
+// since none of them are implemented recursively.
+func TestDeepChain(t *testing.T) {
+	const depth = 100000
+
+	dg := NewDependencyGraphWithCapacity[int](depth)
+	for i := range depth - 1 {
+		dg.Add(i, i+1)
+	}
+
+	dg.Add(depth - 1)
+
+	if !dg.WouldCycle(depth-1, 0) {
+		t.Fatalf("cycle through a deep chain has not been detected")
+	}
+
+	independent, err := dg.Independent(0, depth-1)
+	if err != nil || independent {
+		t.Fatalf("ends of a deep chain are reported as independent: %v", err)
+	}
+
+	path, _, ok := dg.CheapestPath(0, depth-1)
+	if !ok || len(path) != depth {
+		t.Fatalf("path through a deep chain found incorrectly: %d elements", len(path))
+	}
+
+	// The maximum antichain is computed over the transitive closure, which is quadratic in size,
+	// so it is checked on a shorter chain.
+	short := NewDependencyGraphWithCapacity[int](depth / 20)
+	for i := range depth/20 - 1 {
+		short.Add(i, i+1)
+	}
+
+	short.Add(depth/20 - 1)
+
+	if res := short.MaximumAntichain(); len(res) != 1 {
+		t.Fatalf("maximum antichain of a deep chain computed incorrectly: %d elements", len(res))
+	}
+
+	dg.Add(depth-1, 0)
+
+	if fns := dg.FeedbackNodeSet(); len(fns) != 1 {
+		t.Fatalf("feedback node set of a deep cycle computed incorrectly: %v", fns)
+	}
+
+	if _, members := dg.Condensation(); len(members) != 1 || len(members[0]) != depth {
+		t.Fatalf("condensation of a deep cycle computed incorrectly: %d super-nodes", len(members))
+	}
+}
+
+// TestNearCycles tests that the dependencies, which are a single inverted edge away
+// from forming a cycle, are reported within the hop threshold.
+func TestNearCycles(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+
+	// Inverting "ui -> db" into "db -> ui" would close the cycle "ui -> api -> db -> ui",
+	// which both "ui -> api" and "api -> db" are a part of...
+	dg.Add("ui", "api", "db")
+	dg.Add("api", "db")
+	dg.Add("db")
+
+	// ...whereas the diamond is harmless.
+	dg.Add("app", "left", "right")
+	dg.Add("left", "base")
+	dg.Add("right", "base")
+	dg.Add("base")
+
+	if res := dg.NearCycles(1); len(res) != 0 {
+		t.Fatalf("near cycles detected beyond the threshold: %v", res)
+	}
+
+	res := dg.NearCycles(2)
+	if !slices.Equal(res, [][2]string{{"ui", "api"}, {"api", "db"}}) {
+		t.Fatalf("near cycles detected incorrectly: %v", res)
+	}
+
+	dg.Add("db", "ui")
+
+	if res := dg.NearCycles(5); len(res) != 0 {
+		t.Fatalf("dependencies of a real cycle reported as near cycles: %v", res)
+	}
+}
+
+// TestCondensation tests that strongly connected components get collapsed into an acyclic graph.
+func TestCondensation(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "api", "X")
+	dg.Add("api", "db")
+	dg.Add("db", "cache")
+	dg.Add("cache", "api", "log")
+	dg.Add("log")
+	dg.Add("self", "self")
+
+	cg, members := dg.Condensation()
+
+	expected := map[int][]string{
+		0: {"app"},
+		1: {"api", "db", "cache"},
+		2: {"log"},
+		3: {"self"},
+	}
+
+	if !maps.EqualFunc(members, expected, slices.Equal) {
+		t.Fatalf("condensation members computed incorrectly: %v", members)
+	}
+
+	res, err := cg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving condensation: %v", err)
+	}
+
+	if !slices.Equal(res, []int{2, 3, 1, 0}) {
+		t.Fatalf("condensation resolved incorrectly: %v", res)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"log/slog"
+	"slices"
+	"time"
 )
 
 var (
@@ -21,38 +24,300 @@ var (
 	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
 	// instead of a simple comparison.
 	ErrUnknownDependency = errors.New("unknown dependency")
+
+	// ErrUnknownNode is used when an operation refers to a node, which is not present in the graph.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrUnknownNode = errors.New("unknown node")
+
+	// ErrExcludedDependency is used when resolving a graph with exclusions in the strict mode,
+	// and encountering a node, which depends on an excluded node.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrExcludedDependency = errors.New("excluded dependency")
+
+	// ErrUnknownCapability is used when resolving the graph and encountering a node,
+	// which requires a capability that is not provided by any node.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrUnknownCapability = errors.New("unknown capability")
+
+	// ErrDuplicateDependency is used when the graph rejects duplicate arguments,
+	// and the same dependency is listed twice in a single Add call.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrDuplicateDependency = errors.New("duplicate dependency")
+
+	// ErrDisconnectedGraph is used when the graph is required to be connected,
+	// but consists of more than one weakly connected component.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrDisconnectedGraph = errors.New("disconnected graph")
+
+	// ErrAliasCycle is used when an alias would end up referring to itself, either directly or transitively.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrAliasCycle = errors.New("alias cycle")
+
+	// ErrInvalidPrefix is used when a partial ordering, which is supposed to have been completed already,
+	// lists a node before some of its dependencies, or lists the same node twice.
+	// This error may be wrapped; to account for this, use either "errors.Is" or "errors.As"
+	// instead of a simple comparison.
+	ErrInvalidPrefix = errors.New("invalid prefix")
 )
 
+// ResolveError is returned when a graph cannot be resolved.
+// Its underlying error is either ErrCircularDependency, or an error that occurred during the validation,
+// such as ErrUnknownDependency; use "errors.Is" or "errors.As" to check for them.
+type ResolveError struct {
+	// Resolved is the number of elements that have been resolved before the failure.
+	Resolved int
+
+	// Total is the number of elements in the graph.
+	Total int
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("resolving dependency graph after %d of %d elements: %v", e.Resolved, e.Total, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
 type (
 	depList[T comparable] = map[T]struct{}
 	depEdge[T comparable] = struct {
 		name T
 		deps depList[T]
+
+		// depOrder holds the same dependencies as deps, in the order in which they have been added.
+		depOrder []T
+
+		// costs holds the costs of dependencies which have been added with a custom cost;
+		// all other dependencies cost 1.
+		costs map[T]float64
+
+		// provides and requires hold the capabilities, which are provided and required by the edge.
+		provides []string
+		requires []string
+
+		// weak holds the dependencies, which have only been added with AddWeak.
+		weak depList[T]
 	}
 )
 
+// cloneEdge makes a deep copy of an edge, keeping only the dependencies accepted by keepDep.
+func cloneEdge[T comparable](edge *depEdge[T], keepDep func(name, dep T) bool) *depEdge[T] {
+	clone := &depEdge[T]{
+		name: edge.name,
+		deps: make(depList[T], len(edge.deps)),
+	}
+
+	for _, dep := range edge.depOrder {
+		if keepDep(edge.name, dep) {
+			clone.deps[dep] = struct{}{}
+			clone.depOrder = append(clone.depOrder, dep)
+		}
+	}
+
+	for dep, cost := range edge.costs {
+		if _, ok := clone.deps[dep]; ok {
+			if clone.costs == nil {
+				clone.costs = map[T]float64{}
+			}
+
+			clone.costs[dep] = cost
+		}
+	}
+
+	for dep := range edge.weak {
+		if _, ok := clone.deps[dep]; ok {
+			if clone.weak == nil {
+				clone.weak = depList[T]{}
+			}
+
+			clone.weak[dep] = struct{}{}
+		}
+	}
+
+	clone.provides = slices.Clone(edge.provides)
+	clone.requires = slices.Clone(edge.requires)
+
+	return clone
+}
+
 // DependencyGraph represents a stable dependency graph.
 // By stable we mean that it keeps the initial ordering of elements,
 // according to the insertion order, which may only be broken to solve a dependency.
 type DependencyGraph[T comparable] struct {
 	edges   []*depEdge[T]
 	edgeMap map[T]*depEdge[T]
+
+	// shared is set when the edges are referenced by at least one snapshot,
+	// meaning that they must be copied prior to any mutation.
+	shared bool
+
+	// frozen is set when the graph must not be mutated anymore.
+	frozen bool
+
+	// validated is set when the graph has passed the validation and has not been mutated since.
+	validated bool
+
+	// generation is incremented by every mutation, so that a resolution in progress
+	// can tell that the graph has been extended by its caller.
+	generation uint64
+
+	unknownHandler   func(node, dep T) error
+	nodeProvider     func(dep T) ([]T, bool)
+	strictExclusions bool
+	logger           *slog.Logger
+
+	rejectDuplicateArgs bool
+	requireConnected    bool
+	inPlaceResolve      bool
+	autoNormalize       bool
+	cacheReach          bool
+
+	// reach is the transitive closure of the graph, if it is cached.
+	// Since it is only updated incrementally as dependencies get added,
+	// any other mutation drops it, and it gets rebuilt by the next reachability query.
+	reach *reachCache[T]
+
+	pinned map[T]struct{}
+
+	// categoryOf and categoryRanks drive the tie-breaks between free elements of different categories.
+	categoryOf    func(T) int
+	categoryRanks map[int]int
+
+	// exclusive maps every node to the exclusive sets it belongs to,
+	// which are numbered up to exclusiveSets.
+	exclusive     map[T][]int
+	exclusiveSets int
+
+	// aliases maps every alias to its canonical name.
+	// It is never modified in-place, so that it can be shared with snapshots.
+	aliases map[T]T
 }
 
 // NewDependencyGraph creates a new stable dependency graph.
-func NewDependencyGraph[T comparable]() *DependencyGraph[T] {
-	return &DependencyGraph[T]{
+// Its behavior may be tuned by passing one or more options.
+func NewDependencyGraph[T comparable](opts ...Option[T]) *DependencyGraph[T] {
+	dg := &DependencyGraph[T]{
 		edgeMap: map[T]*depEdge[T]{},
 	}
+
+	for _, opt := range opts {
+		opt(dg)
+	}
+
+	return dg
+}
+
+// NewDependencyGraphWithCapacity creates a new stable dependency graph with enough space
+// preallocated to hold n elements, avoiding reallocations while the graph is being built.
+// Its behavior may be tuned by passing one or more options.
+func NewDependencyGraphWithCapacity[T comparable](n int, opts ...Option[T]) *DependencyGraph[T] {
+	dg := NewDependencyGraph(opts...)
+	dg.edges = make([]*depEdge[T], 0, n)
+	dg.edgeMap = make(map[T]*depEdge[T], n)
+
+	return dg
+}
+
+// Validate checks that all dependencies of the graph's elements are known to the graph,
+// consulting the unknown dependency handler, if it is set, for each unknown dependency.
+// Once the validation passes, it is not performed again, either by Validate or by the resolution,
+// until the graph gets mutated; frozen graphs are the exception, since they are always revalidated
+// in order to stay safe for concurrent use.
+func (dg *DependencyGraph[T]) Validate() error {
+	if dg.validated {
+		return nil
+	}
+
+	err := dg.validate()
+	if err != nil {
+		return err
+	}
+
+	if !dg.frozen {
+		dg.validated = true
+	}
+
+	return nil
 }
 
-// validate iterates over all graph edges and checks if their dependencies exist.
+// validate iterates over all graph edges and checks if their dependencies exist, as described by validateDeps,
+// then runs the checks covering the whole graph.
 func (dg *DependencyGraph[T]) validate() error {
-	for _, edge := range dg.edgeMap {
-		for dep := range edge.deps {
-			if _, ok := dg.edgeMap[dep]; !ok {
+	// The edge list is clipped, so that appending the supplied nodes never overwrites the graph's own list.
+	err := dg.validateDeps(slices.Clip(dg.edges))
+	if err != nil {
+		return err
+	}
+
+	if dg.autoNormalize && !dg.frozen {
+		dg.Normalize()
+	}
+
+	err = dg.validateCapabilities()
+	if err != nil {
+		return err
+	}
+
+	if dg.requireConnected {
+		return dg.validateConnected()
+	}
+
+	return nil
+}
+
+// validateDeps checks if the dependencies of the given edges exist.
+// If a node provider is set, it gets asked to supply each unknown dependency first;
+// then, if an unknown dependency handler is set, it gets to decide the fate of each remaining one.
+func (dg *DependencyGraph[T]) validateDeps(edges []*depEdge[T]) error {
+	// Nodes supplied by the node provider are appended to the edges while iterating over them,
+	// so that their dependencies get validated as well.
+	for i := 0; i < len(edges); i++ {
+		edge := edges[i]
+
+		for _, dep := range edge.depOrder {
+			if _, ok := dg.edgeMap[dep]; ok {
+				continue
+			}
+
+			// Weak dependencies never pull their nodes in.
+			if _, ok := edge.weak[dep]; ok {
+				continue
+			}
+
+			if dg.nodeProvider != nil && !dg.frozen {
+				if deps, ok := dg.nodeProvider(dep); ok {
+					dg.Add(dep, deps...)
+					edges = append(edges, dg.edgeMap[dg.resolveAlias(dep)])
+
+					continue
+				}
+			}
+
+			// The dependency gets dropped by the normalization, which follows the validation.
+			if dg.autoNormalize && !dg.frozen {
+				continue
+			}
+
+			if dg.unknownHandler == nil {
 				return fmt.Errorf("looking up dependency \"%v\": %w", dep, ErrUnknownDependency)
 			}
+
+			err := dg.unknownHandler(edge.name, dep)
+			if err != nil {
+				return fmt.Errorf("handling unknown dependency \"%v\" of \"%v\": %w", dep, edge.name, err)
+			}
 		}
 	}
 
@@ -62,7 +327,26 @@ func (dg *DependencyGraph[T]) validate() error {
 // Add adds an element to the end of dependency graph's edge list.
 // It may be called multiple times during the graph construction,
 // in which case, its dependencies get concatenated together.
+// Dependencies are deduplicated: listing the same dependency more than once,
+// either in a single call or across several calls, has the same effect as listing it once.
+// The resolution order depends only on the final set of dependencies and on the order,
+// in which the elements have first been added, never on how the dependencies were split across calls.
+// If the graph has been created with WithRejectDuplicateArgs, Add panics instead
+// when the same dependency is listed twice in a single call.
 func (dg *DependencyGraph[T]) Add(name T, deps ...T) {
+	if dg.rejectDuplicateArgs {
+		checkDuplicateArgs(name, deps)
+	}
+
+	// Adding dependencies is the only mutation, which the reachability cache survives.
+	reach := dg.reach
+	dg.mutate()
+	dg.reach = reach
+
+	if len(dg.aliases) > 0 {
+		name, deps = dg.canonicalize(name, deps)
+	}
+
 	// Determine whether we already have this edge.
 	edge, ok := dg.edgeMap[name]
 	if !ok {
@@ -79,76 +363,331 @@ func (dg *DependencyGraph[T]) Add(name T, deps ...T) {
 	// Irregardless of whether this edge is new or existing,
 	// add all deps to its dep list.
 	for _, dep := range deps {
-		edge.deps[dep] = struct{}{}
+		if _, ok := edge.deps[dep]; !ok {
+			edge.deps[dep] = struct{}{}
+			edge.depOrder = append(edge.depOrder, dep)
+
+			if dg.reach != nil {
+				dg.reach.add(name, dep)
+			}
+		}
+
+		// A regular dependency supersedes a weak one.
+		delete(edge.weak, dep)
 	}
 }
 
+// checkDuplicateArgs panics with ErrDuplicateDependency if deps contain duplicates.
+func checkDuplicateArgs[T comparable](name T, deps []T) {
+	seen := make(map[T]struct{}, len(deps))
+
+	for _, dep := range deps {
+		if _, ok := seen[dep]; ok {
+			panic(fmt.Errorf("depgraph: adding \"%v\" with dependency \"%v\" listed twice: %w", name, dep, ErrDuplicateDependency))
+		}
+
+		seen[dep] = struct{}{}
+	}
+}
+
+// Clear removes all elements from the graph, while retaining its allocated capacity,
+// so that rebuilding the graph to a similar size does not require growing it again.
+// Aliases are removed as well, while the graph's options are retained.
+func (dg *DependencyGraph[T]) Clear() {
+	dg.checkFrozen()
+	dg.validated = false
+	dg.aliases = nil
+	dg.reach = nil
+
+	if dg.shared {
+		// The edges belong to a snapshot, so they must be left intact.
+		dg.edges = make([]*depEdge[T], 0, cap(dg.edges))
+		dg.edgeMap = make(map[T]*depEdge[T], len(dg.edgeMap))
+		dg.shared = false
+
+		return
+	}
+
+	// Drop the edge pointers, so that they can be garbage-collected.
+	clear(dg.edges)
+	dg.edges = dg.edges[:0]
+	clear(dg.edgeMap)
+}
+
 // ResolveIter returns an iterator that yields the graph's elements in dependency order.
+// The graph's edge list is left intact, unless the graph has been created with WithInPlaceResolve.
 // If a circular dependency is detected, or if the graph is invalid,
 // the iterator yields a pair of (zero element, error) and stops.
+// The yielded error is always a *ResolveError, which tells how many elements
+// have been yielded before the failure.
+//
+// The graph may be extended while iterating, with Add or any of the methods built on top of it,
+// such as AddBefore, AddAfter, AddWeak, AddProvider and AddConsumer; the rest of the resolution takes the changes into account,
+// after validating the graph once more:
+//   - the elements added in the meantime get resolved by the same pass, and so do the nodes
+//     supplied by the node provider;
+//   - dependencies on the elements, which have already been yielded, are satisfied right away;
+//   - an element, which has not been yielded yet, waits for its new dependencies,
+//     even if it has already become free, in which case it gets blocked again;
+//   - dependencies added to the elements, which have already been yielded, cannot be honored anymore
+//     and are only taken into account by the later resolutions.
+//
+// Any other mutation during the iteration, such as Alias, Normalize, MergeWith, Clear or Restore,
+// leads to an undefined result.
 func (dg *DependencyGraph[T]) ResolveIter() iter.Seq2[T, error] {
+	return dg.resolveIter(&resolveScratch[T]{})
+}
+
+// resolveScratch holds the buffers used by a resolution, which may be retained between resolutions.
+type resolveScratch[T comparable] struct {
+	edges     []*depEdge[T]
+	refcounts map[T]int
+}
+
+// resolveIter implements ResolveIter, keeping its working state in the scratch buffers.
+func (dg *DependencyGraph[T]) resolveIter(scratch *resolveScratch[T]) iter.Seq2[T, error] {
 	return func(yield func(T, error) bool) {
 		var zero T
 
-		err := dg.validate()
+		err := dg.Validate()
 		if err != nil {
-			yield(zero, fmt.Errorf("validating dependency graph: %w", err))
+			yield(zero, &ResolveError{
+				Total: len(dg.edges),
+				Err:   fmt.Errorf("validating dependency graph: %w", err),
+			})
 			return
 		}
 
-		fmax := 0
-		edges := dg.edges
-		refcounts := make(map[T]int, len(edges))
+		var edges []*depEdge[T]
+
+		if dg.inPlaceResolve && !dg.frozen {
+			// The edge list gets reordered in-place, so make sure that no snapshot is affected.
+			dg.own()
+			edges = dg.edges
+		} else {
+			// Resolve on a copy, so that the edge list order, which drives the tie-breaks
+			// of later resolutions, stays intact; a frozen graph may also be resolved concurrently.
+			scratch.edges = append(scratch.edges[:0], dg.edges...)
+			edges = scratch.edges
+		}
+
+		if scratch.refcounts == nil {
+			scratch.refcounts = make(map[T]int, len(edges))
+		}
+
+		refcounts := scratch.refcounts
+		clear(refcounts)
 
 		// Save the current number of dependencies for each edge.
+		// Unknown dependencies that have survived the validation are treated as satisfied,
+		// so they are not accounted for.
 		for _, edge := range edges {
-			refcounts[edge.name] = len(edge.deps)
+			for _, dep := range edge.depOrder {
+				if _, ok := dg.edgeMap[dep]; ok {
+					refcounts[edge.name]++
+				}
+			}
 		}
 
-		// Promote all free edges to the start of the edge list,
-		// whilst keeping the stable ordering.
-		for i, edge := range edges {
-			if len(edge.deps) == 0 {
-				edges[fmax], edges[i] = edges[i], edges[fmax]
-				fmax++
+		var extendErr error
+
+		extend := func(resolved, fmax int) ([]*depEdge[T], int, bool) {
+			edges, fmax, extendErr = dg.extendResolution(edges, refcounts, resolved, fmax)
+			if !dg.inPlaceResolve || dg.frozen {
+				scratch.edges = edges
 			}
+
+			return edges, fmax, extendErr == nil
 		}
 
-		// Keep iterating while we still have at least one remaining free edge.
-		for fcur := 0; fcur < fmax; fcur++ {
-			this := edges[fcur]
+		fmax, ok := dg.resolveEdges(edges, refcounts, func(edge *depEdge[T]) bool {
+			return yield(edge.name, nil)
+		}, extend)
 
-			// Since this edge has no dependencies - yield it to our caller.
-			if !yield(this.name, nil) {
-				return
+		if extendErr != nil {
+			yield(zero, &ResolveError{
+				Resolved: fmax,
+				Total:    len(dg.edges),
+				Err:      fmt.Errorf("validating dependency graph: %w", extendErr),
+			})
+			return
+		}
+
+		// If we stopped before reaching fmax,
+		// not all edges have been processed, thus there is a circular dependency.
+		if ok && fmax != len(edges) {
+			yield(zero, &ResolveError{
+				Resolved: fmax,
+				Total:    len(edges),
+				Err:      ErrCircularDependency,
+			})
+		}
+	}
+}
+
+// extendResolution brings a resolution in progress up to date with the graph, after it has been extended
+// by the caller: the first resolved edges have already been yielded, and the edges up to fmax are free.
+// The graph gets validated once more, and the unresolved edges are rebuilt from the graph's edge list,
+// so that the added edges are picked up wherever they have been inserted; the free edges, which are still
+// free, keep their order, followed by the remaining unresolved edges in the edge list order.
+// The refcounts of the unresolved edges are recomputed, so the free edges, which got blocked
+// by new dependencies, are moved back among the blocked ones.
+// It returns the updated edges, along with the new end of the free ones.
+func (dg *DependencyGraph[T]) extendResolution(edges []*depEdge[T], refcounts map[T]int, resolved, fmax int) ([]*depEdge[T], int, error) {
+	err := dg.Validate()
+	if err != nil {
+		return edges, resolved, err
+	}
+
+	done := make(map[T]struct{}, resolved)
+	for _, edge := range edges[:resolved] {
+		done[edge.name] = struct{}{}
+	}
+
+	for _, edge := range dg.edges {
+		if _, ok := done[edge.name]; ok {
+			continue
+		}
+
+		refcounts[edge.name] = 0
+
+		for _, dep := range edge.depOrder {
+			if _, ok := dg.edgeMap[dep]; !ok {
+				continue
 			}
 
-			// If a later edge depends on this edge - clear the (already resolved) dependency.
-			// If, after clearing, an edge becomes free - promote it to the free list.
-			for i := fcur + 1; i < len(edges); i++ {
-				if _, ok := edges[i].deps[this.name]; ok {
-					// We can't really clear a dependency because that would require tracking
-					// a lot of state; however, we can simply decrease the reference counter.
-					// This is enough to track when an edge becomes free.
-					refcounts[edges[i].name]--
-
-					if refcounts[edges[i].name] == 0 {
-						// Promote the edge.
-						edges[fmax], edges[i] = edges[i], edges[fmax]
-						fmax++
-					}
+			if _, ok := done[dep]; !ok {
+				refcounts[edge.name]++
+			}
+		}
+	}
+
+	// The edges are looked up by their names, since they may have been copied from beneath a snapshot.
+	rebuilt := make([]*depEdge[T], 0, len(dg.edges))
+	for _, edge := range edges[:resolved] {
+		rebuilt = append(rebuilt, dg.edgeMap[edge.name])
+	}
+
+	// From now on, done holds every edge, which has already been placed.
+	for _, edge := range edges[resolved:fmax] {
+		if refcounts[edge.name] == 0 {
+			done[edge.name] = struct{}{}
+			rebuilt = append(rebuilt, dg.edgeMap[edge.name])
+		}
+	}
+
+	fmax = len(rebuilt)
+
+	for _, edge := range dg.edges {
+		if _, ok := done[edge.name]; !ok {
+			rebuilt = append(rebuilt, edge)
+		}
+	}
+
+	if dg.inPlaceResolve && !dg.frozen {
+		dg.edges = rebuilt
+	}
+
+	return rebuilt, fmax, nil
+}
+
+// resolveEdges runs the stable resolution algorithm over the edge list, reordering it in-place.
+// The refcounts must hold the number of unsatisfied dependencies for each edge; they get consumed as well.
+// Each resolved edge is passed to yield; if yield returns false, the resolution stops early.
+// Pinned edges are chosen before all other free edges, followed by the edges of the earliest category,
+// and if a logger is set, the algorithm's decisions are traced at the debug level.
+// If extend is not nil, it gets called whenever the graph has been mutated by yield, with the number
+// of resolved edges and the end of the free ones; it must return the updated edges, with the refcounts
+// brought up to date, along with the new end of the free ones, or false to abort the resolution.
+// It returns the number of resolved edges, and whether the resolution has run to its end.
+func (dg *DependencyGraph[T]) resolveEdges(edges []*depEdge[T], refcounts map[T]int, yield func(*depEdge[T]) bool,
+	extend func(resolved, fmax int) ([]*depEdge[T], int, bool),
+) (int, bool) {
+	fmax := 0
+	generation := dg.generation
+
+	promote := func(i int) {
+		if dg.logger != nil {
+			dg.logger.Debug("promoting dependency graph node", "node", edges[i].name, "satisfied", len(edges[i].deps))
+		}
+
+		edges[fmax], edges[i] = edges[i], edges[fmax]
+		fmax++
+	}
+
+	// Promote all free edges to the start of the edge list,
+	// whilst keeping the stable ordering.
+	for i, edge := range edges {
+		if refcounts[edge.name] == 0 {
+			promote(i)
+		}
+	}
+
+	// Keep iterating while we still have at least one remaining free edge.
+	for fcur := 0; fcur < fmax; fcur++ {
+		if len(dg.pinned) > 0 {
+			dg.pickPinned(edges[fcur:fmax])
+		}
+
+		if dg.categoryOf != nil {
+			if _, ok := dg.pinned[edges[fcur].name]; !ok {
+				dg.pickCategory(edges[fcur:fmax])
+			}
+		}
+
+		this := edges[fcur]
+
+		if dg.logger != nil {
+			dg.logger.Debug("resolving dependency graph node", "node", this.name, "position", fcur)
+		}
+
+		// Since this edge has no dependencies - yield it to our caller.
+		if !yield(this) {
+			return fcur, false
+		}
+
+		if extend != nil && dg.generation != generation {
+			var ok bool
+
+			edges, fmax, ok = extend(fcur+1, fmax)
+			if !ok {
+				return fcur + 1, false
+			}
+
+			generation = dg.generation
+
+			// The refcounts already account for this edge, so only the newly freed edges are left to promote.
+			for i := fmax; i < len(edges); i++ {
+				if refcounts[edges[i].name] == 0 {
+					promote(i)
 				}
 			}
+
+			continue
 		}
 
-		// If we stopped before reaching fmax,
-		// not all edges have been processed, thus there is a circular dependency.
-		if fmax != len(edges) {
-			yield(zero, ErrCircularDependency)
+		// If a later edge depends on this edge - clear the (already resolved) dependency.
+		// If, after clearing, an edge becomes free - promote it to the free list.
+		for i := fcur + 1; i < len(edges); i++ {
+			if _, ok := edges[i].deps[this.name]; ok {
+				// We can't really clear a dependency because that would require tracking
+				// a lot of state; however, we can simply decrease the reference counter.
+				// This is enough to track when an edge becomes free.
+				refcounts[edges[i].name]--
+
+				if refcounts[edges[i].name] == 0 {
+					// Promote the edge.
+					promote(i)
+				}
+			}
 		}
 	}
+
+	return fmax, true
 }
 
+// Resolve returns the graph's elements in dependency order.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
 func (dg *DependencyGraph[T]) Resolve() ([]T, error) {
 	// The resulting slice will be the same length as the graph's edge count,
 	// therefore allocate all the memory beforehand.
@@ -164,3 +703,110 @@ func (dg *DependencyGraph[T]) Resolve() ([]T, error) {
 
 	return res, nil
 }
+
+// adjacency builds an index-based representation of the graph, where every edge is referred to
+// by its position in the edge list.
+// For each edge, it returns the number of its known dependencies,
+// as well as the list of edges that depend on it, in the edge list order.
+// Unknown dependencies are not accounted for.
+func (dg *DependencyGraph[T]) adjacency() ([]int, [][]int) {
+	index := make(map[T]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		index[edge.name] = i
+	}
+
+	refcounts := make([]int, len(dg.edges))
+	dependents := make([][]int, len(dg.edges))
+
+	for i, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			if j, ok := index[dep]; ok {
+				refcounts[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	return refcounts, dependents
+}
+
+// depIndices returns, for each edge, the positions of its known dependencies in the edge list.
+func (dg *DependencyGraph[T]) depIndices() [][]int {
+	index := make(map[T]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		index[edge.name] = i
+	}
+
+	deps := make([][]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			if j, ok := index[dep]; ok {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+
+	return deps
+}
+
+// ResolveTimeout is like Resolve, but stops once the given duration elapses,
+// returning the elements, which had been resolved by then.
+// Since the elements are resolved incrementally, the partial result is always a valid prefix
+// of the full dependency order, so the caller may process it right away.
+// The boolean result reports whether the resolution has been completed.
+// If the graph cannot be resolved, the elements resolved before the failure are returned
+// along with a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveTimeout(d time.Duration) ([]T, bool, error) {
+	deadline := time.Now().Add(d)
+	res := make([]T, 0, len(dg.edges))
+
+	for el, err := range dg.ResolveIter() {
+		if err != nil {
+			return res, false, err
+		}
+
+		if !time.Now().Before(deadline) {
+			return res, false, nil
+		}
+
+		res = append(res, el)
+	}
+
+	return res, true, nil
+}
+
+// MustResolve is like Resolve, but panics if the graph cannot be resolved.
+// It simplifies the initialization of graphs, which are known to be valid,
+// such as the ones in test fixtures.
+func (dg *DependencyGraph[T]) MustResolve() []T {
+	res, err := dg.Resolve()
+	if err != nil {
+		panic(fmt.Errorf("depgraph: MustResolve: %w", err))
+	}
+
+	return res
+}
+
+// ResolveReverseIter returns an iterator that yields the graph's elements in the exact reverse
+// of the dependency order, meaning that dependents come before their dependencies.
+// This is the natural order for tearing things down.
+// Since the last resolved element has to be known before anything gets yielded,
+// the whole graph is resolved upfront; therefore, in case of an error,
+// the iterator yields a pair of (zero element, error) without yielding any elements beforehand.
+func (dg *DependencyGraph[T]) ResolveReverseIter() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		res, err := dg.Resolve()
+		if err != nil {
+			var zero T
+			yield(zero, err)
+
+			return
+		}
+
+		for i := len(res) - 1; i >= 0; i-- {
+			if !yield(res[i], nil) {
+				return
+			}
+		}
+	}
+}
@@ -2,8 +2,10 @@ package depgraph
 
 import (
 	"errors"
+	"math/rand/v2"
 	"slices"
 	"testing"
+	"time"
 )
 
 // TestResolve tests the dependency resolution algorithm.
@@ -263,3 +265,533 @@ func TestPointers(t *testing.T) {
 		t.Fatalf("pointer graph resolved incorrectly: %v", res)
 	}
 }
+
+// TestReverseIter tests that ResolveReverseIter yields elements in the reverse resolution order,
+// and that it allows us to exit early.
+func TestReverseIter(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "B", "A")
+	dg.Add("E")
+
+	res := []string{}
+	for el, err := range dg.ResolveReverseIter() {
+		if err != nil {
+			t.Fatalf("resolving graph in reverse: %v", err)
+		}
+
+		res = append(res, el)
+		if el == "C" {
+			break
+		}
+	}
+
+	if !slices.Equal(res, []string{"D", "B", "E", "C"}) {
+		t.Fatalf("reverse graph resolved incorrectly: %v", res)
+	}
+
+	dg.Add("A", "D")
+
+	for el, err := range dg.ResolveReverseIter() {
+		if !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("reverse-resolved circular graph: %v, %v", el, err)
+		}
+	}
+}
+
+// TestWithCapacity tests that a graph created with a capacity hint gets preallocated
+// and still resolves correctly.
+func TestWithCapacity(t *testing.T) {
+	dg := NewDependencyGraphWithCapacity[int](100)
+	if cap(dg.edges) != 100 {
+		t.Fatalf("graph edge list is not preallocated: capacity = %d", cap(dg.edges))
+	}
+
+	for i := range 100 {
+		if i == 0 {
+			dg.Add(i)
+		} else {
+			dg.Add(i, i-1)
+		}
+	}
+
+	if cap(dg.edges) != 100 {
+		t.Fatalf("graph edge list got reallocated: capacity = %d", cap(dg.edges))
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving preallocated graph: %v", err)
+	}
+
+	for i, el := range res {
+		if el != i {
+			t.Fatalf("preallocated graph resolved incorrectly: %v", res)
+		}
+	}
+}
+
+// TestClear tests that clearing the graph removes all of its elements,
+// retains the capacity and keeps snapshots intact.
+func TestClear(t *testing.T) {
+	dg := NewDependencyGraphWithCapacity[string](10)
+	dg.Add("A")
+	dg.Add("B", "A")
+
+	snap := dg.Snapshot()
+	dg.Clear()
+
+	if len(dg.edges) != 0 || len(dg.edgeMap) != 0 || cap(dg.edges) != 10 {
+		t.Fatalf("graph cleared incorrectly: len = %d; cap = %d", len(dg.edges), cap(dg.edges))
+	}
+
+	dg.Add("C", "B")
+
+	_, err := dg.Resolve()
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("resolved cleared graph with unknown dependency: %v", err)
+	}
+
+	dg.Clear()
+
+	if len(dg.edges) != 0 || len(dg.edgeMap) != 0 || cap(dg.edges) != 10 {
+		t.Fatalf("graph cleared incorrectly: len = %d; cap = %d", len(dg.edges), cap(dg.edges))
+	}
+
+	dg.Restore(snap)
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving restored graph after clearing: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("restored graph resolved incorrectly after clearing: %v", res)
+	}
+}
+
+// TestResolveError tests that resolution errors report how far the resolution got.
+func TestResolveError(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "C")
+	dg.Add("C", "B")
+	dg.Add("D", "A")
+	dg.Add("E", "X")
+
+	var resErr *ResolveError
+
+	_, err := dg.Resolve()
+	if !errors.As(err, &resErr) || !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("unexpected error when resolving graph with unknown dependency: %v", err)
+	}
+
+	if resErr.Resolved != 0 || resErr.Total != 5 {
+		t.Fatalf("unexpected progress of graph with unknown dependency: %d of %d", resErr.Resolved, resErr.Total)
+	}
+
+	dg.Add("X")
+
+	_, err = dg.Resolve()
+	if !errors.As(err, &resErr) || !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("unexpected error when resolving graph with circular dependency: %v", err)
+	}
+
+	if resErr.Resolved != 4 || resErr.Total != 6 {
+		t.Fatalf("unexpected progress of graph with circular dependency: %d of %d", resErr.Resolved, resErr.Total)
+	}
+
+	if err.Error() != "resolving dependency graph after 4 of 6 elements: circular dependency" {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+// TestResolveErrorInvalid tests that the resolution variants report an invalid graph with a *ResolveError.
+func TestResolveErrorInvalid(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "X")
+
+	tbl := []struct {
+		name    string
+		resolve func() error
+	}{
+		{name: "ResolveByDepth", resolve: func() error {
+			_, err := dg.ResolveByDepth()
+			return err
+		}},
+		{name: "ResolveBatchIter", resolve: func() error {
+			for _, err := range dg.ResolveBatchIter() {
+				return err
+			}
+
+			return nil
+		}},
+		{name: "Plan", resolve: func() error {
+			_, err := dg.Plan()
+			return err
+		}},
+		{name: "ResolveWithFailures", resolve: func() error {
+			_, _, err := dg.ResolveWithFailures(func(string) bool { return false })
+			return err
+		}},
+	}
+
+	for _, test := range tbl {
+		var resErr *ResolveError
+
+		err := test.resolve()
+		if !errors.As(err, &resErr) || !errors.Is(err, ErrUnknownDependency) || resErr.Total != 1 {
+			t.Fatalf("%s: unexpected error when resolving invalid graph: %v", test.name, err)
+		}
+	}
+}
+
+// TestValidate tests that the validation is performed only when the graph has changed.
+func TestValidate(t *testing.T) {
+	calls := 0
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		calls++
+		return nil
+	}))
+
+	dg.Add("A", "X")
+
+	err := dg.Validate()
+	if err != nil {
+		t.Fatalf("validating graph: %v", err)
+	}
+
+	_, err = dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving validated graph: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("unchanged graph validated more than once: %d", calls)
+	}
+
+	dg.Add("B")
+
+	_, err = dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving changed graph: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("changed graph has not been revalidated: %d", calls)
+	}
+
+	dg = NewDependencyGraph[string]()
+	dg.Add("A", "X")
+
+	err = dg.Validate()
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("validated graph with unknown dependency: %v", err)
+	}
+
+	dg.Add("X")
+
+	err = dg.Validate()
+	if err != nil {
+		t.Fatalf("validating fixed graph: %v", err)
+	}
+}
+
+// TestMustResolve tests that MustResolve panics with the resolution error.
+func TestMustResolve(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("B", "A")
+	dg.Add("A")
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("graph resolved incorrectly: %v", res)
+	}
+
+	dg.Add("A", "B")
+
+	defer func() {
+		err, ok := recover().(error)
+		if !ok || !errors.Is(err, ErrCircularDependency) {
+			t.Fatalf("unexpected panic when resolving graph with circular dependency: %v", err)
+		}
+	}()
+
+	dg.MustResolve()
+}
+
+// TestResolveTimeout tests that the resolution stops after the timeout, and that it completes otherwise.
+func TestResolveTimeout(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("B", "A")
+	dg.Add("A")
+
+	res, done, err := dg.ResolveTimeout(time.Hour)
+	if err != nil || !done || !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("graph resolved with timeout incorrectly: %v, %v, %v", res, done, err)
+	}
+
+	res, done, err = dg.ResolveTimeout(0)
+	if err != nil || done || len(res) != 0 {
+		t.Fatalf("graph resolved with an elapsed timeout incorrectly: %v, %v, %v", res, done, err)
+	}
+
+	dg.Add("C", "D")
+	dg.Add("D", "C")
+
+	res, done, err = dg.ResolveTimeout(time.Hour)
+	if !errors.Is(err, ErrCircularDependency) || done || !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("graph with circular dependency resolved with timeout incorrectly: %v, %v, %v", res, done, err)
+	}
+}
+
+// TestBatchedAdd tests that the resolution order does not depend on how the dependencies
+// were split across Add calls, by comparing random graphs built in both ways.
+func TestBatchedAdd(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	for range 500 {
+		n := 1 + rng.IntN(15)
+		deps := make([][]int, n)
+
+		for i := range n {
+			for range rng.IntN(4) {
+				deps[i] = append(deps[i], rng.IntN(n))
+			}
+		}
+
+		single := NewDependencyGraph[int]()
+		batched := NewDependencyGraph[int]()
+
+		for i := range n {
+			single.Add(i, deps[i]...)
+			batched.Add(i)
+		}
+
+		// Add the dependencies one by one, in a random order.
+		pending := [][2]int{}
+		for i := range n {
+			for _, dep := range deps[i] {
+				pending = append(pending, [2]int{i, dep})
+			}
+		}
+
+		rng.Shuffle(len(pending), func(i, j int) {
+			pending[i], pending[j] = pending[j], pending[i]
+		})
+
+		for _, edge := range pending {
+			batched.Add(edge[0], edge[1])
+		}
+
+		expected, expectedErr := single.Resolve()
+		res, err := batched.Resolve()
+
+		if !slices.Equal(res, expected) || errors.Is(err, ErrCircularDependency) != errors.Is(expectedErr, ErrCircularDependency) {
+			t.Fatalf("batched graph resolved differently: deps = %v; output = %v, %v; expected = %v, %v",
+				deps, res, err, expected, expectedErr)
+		}
+	}
+}
+
+// TestResolveIterExtend tests that the graph may be extended while iterating over its resolution:
+// added elements get resolved by the same pass, and free elements get blocked by new dependencies.
+func TestResolveIterExtend(t *testing.T) {
+	for _, inPlace := range []bool{false, true} {
+		opts := []Option[string]{}
+		if inPlace {
+			opts = append(opts, WithInPlaceResolve[string]())
+		}
+
+		dg := NewDependencyGraph(opts...)
+		dg.Add("A")
+		dg.Add("B", "A")
+		dg.Add("C")
+
+		res := []string{}
+
+		for el, err := range dg.ResolveIter() {
+			if err != nil {
+				t.Fatalf("resolving extended graph: in-place = %v: %v", inPlace, err)
+			}
+
+			res = append(res, el)
+
+			switch el {
+			case "A":
+				// C is already free, but has to wait for E now.
+				dg.Add("D", "A")
+				dg.Add("C", "E")
+				dg.Add("E")
+			case "B":
+				// A has already been yielded, so this one cannot be honored anymore.
+				dg.Add("A", "C")
+			}
+		}
+
+		if !slices.Equal(res, []string{"A", "B", "D", "E", "C"}) {
+			t.Fatalf("extended graph resolved incorrectly: in-place = %v; output = %v", inPlace, res)
+		}
+
+		dg = NewDependencyGraph(opts...)
+		dg.Add("A")
+		dg.Add("B")
+
+		var resErr *ResolveError
+
+		for el, err := range dg.ResolveIter() {
+			if el == "A" {
+				dg.Add("C", "X")
+			}
+
+			if err != nil && !errors.As(err, &resErr) {
+				t.Fatalf("unexpected error when resolving extended graph: %v", err)
+			}
+		}
+
+		if resErr == nil || !errors.Is(resErr, ErrUnknownDependency) || resErr.Resolved != 1 || resErr.Total != 3 {
+			t.Fatalf("resolved graph extended with unknown dependency: in-place = %v: %v", inPlace, resErr)
+		}
+
+		// An unknown dependency, which the handler accepts, never blocks the added element.
+		dg = NewDependencyGraph(append(opts, WithUnknownHandler(func(_, _ string) error {
+			return nil
+		}))...)
+		dg.Add("A")
+		dg.Add("B")
+
+		res = []string{}
+
+		for el, err := range dg.ResolveIter() {
+			if err != nil {
+				t.Fatalf("resolving graph extended with accepted unknown dependency: in-place = %v: %v", inPlace, err)
+			}
+
+			res = append(res, el)
+
+			if el == "A" {
+				dg.Add("C", "X", "B")
+			}
+		}
+
+		if !slices.Equal(res, []string{"A", "B", "C"}) {
+			t.Fatalf("graph extended with accepted unknown dependency resolved incorrectly: in-place = %v; output = %v", inPlace, res)
+		}
+	}
+}
+
+// TestResolveIterExtendAt tests that the elements inserted in the middle of the edge list while iterating
+// get resolved by the same pass, exactly once.
+func TestResolveIterExtendAt(t *testing.T) {
+	for _, inPlace := range []bool{false, true} {
+		opts := []Option[string]{}
+		if inPlace {
+			opts = append(opts, WithInPlaceResolve[string]())
+		}
+
+		dg := NewDependencyGraph(opts...)
+		dg.Add("A")
+		dg.Add("B")
+		dg.Add("C")
+
+		res := []string{}
+
+		for el, err := range dg.ResolveIter() {
+			if err != nil {
+				t.Fatalf("resolving extended graph: in-place = %v: %v", inPlace, err)
+			}
+
+			res = append(res, el)
+
+			if el != "A" {
+				continue
+			}
+
+			// B is already free, but has to wait for M now.
+			for _, add := range []func() error{
+				func() error { return dg.AddBefore("A", "N") },
+				func() error { return dg.AddAfter("A", "M") },
+				func() error { return dg.AddBefore("N", "B", "M") },
+			} {
+				err := add()
+				if err != nil {
+					t.Fatalf("extending graph: %v", err)
+				}
+			}
+		}
+
+		if !slices.Equal(res, []string{"A", "C", "N", "M", "B"}) {
+			t.Fatalf("graph extended in the middle resolved incorrectly: in-place = %v; output = %v", inPlace, res)
+		}
+
+		// The in-place resolution has reordered the edge list, so only the default one is comparable.
+		if inPlace {
+			continue
+		}
+
+		if res := dg.MustResolve(); !slices.Equal(res, []string{"N", "A", "M", "C", "B"}) {
+			t.Fatalf("extended graph resolved incorrectly afterwards: %v", res)
+		}
+	}
+}
+
+// FuzzResolve tests that arbitrary sequences of additions, including self-loops, duplicate and unknown
+// dependencies and cycles, never make the resolution panic, and that it either returns
+// a valid dependency order or fails with one of the expected errors.
+// Every addition is encoded as a node byte, followed by a byte holding the number of its dependencies,
+// followed by the dependencies themselves.
+func FuzzResolve(f *testing.F) {
+	f.Add([]byte{0, 0, 1, 1, 0, 2, 2, 1, 0}, false)
+	f.Add([]byte{0, 1, 0}, false)
+	f.Add([]byte{0, 2, 1, 1, 1, 1, 0}, true)
+	f.Add([]byte{0, 1, 17, 2, 3, 0, 0, 1}, true)
+
+	f.Fuzz(func(t *testing.T, data []byte, inplace bool) {
+		opts := []Option[string]{}
+		if inplace {
+			opts = append(opts, WithInPlaceResolve[string]())
+		}
+
+		dg := NewDependencyGraph(opts...)
+
+		// Only the first 16 nodes ever get added, so the rest end up as unknown dependencies.
+		node := func(b byte) string {
+			return string(rune('A' + b%20))
+		}
+
+		for len(data) >= 2 {
+			name := string(rune('A' + data[0]%16))
+			n := min(int(data[1]%4), len(data)-2)
+
+			deps := make([]string, n)
+			for i := range n {
+				deps[i] = node(data[2+i])
+			}
+
+			dg.Add(name, deps...)
+			data = data[2+n:]
+		}
+
+		// Resolve twice, since an in-place resolution reorders the edge list.
+		for range 2 {
+			res, err := dg.Resolve()
+			if err != nil {
+				if !errors.Is(err, ErrCircularDependency) && !errors.Is(err, ErrUnknownDependency) {
+					t.Fatalf("resolution failed with unexpected error: %v", err)
+				}
+
+				var resErr *ResolveError
+				if !errors.As(err, &resErr) || resErr.Total != len(dg.edges) || resErr.Resolved > resErr.Total {
+					t.Fatalf("resolution failed with malformed error: %v", err)
+				}
+
+				continue
+			}
+
+			if !isDependencyOrder(dg, res) {
+				t.Fatalf("graph resolved into an invalid order: %v", res)
+			}
+		}
+	})
+}
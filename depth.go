@@ -0,0 +1,176 @@
+package depgraph
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// ResolveByDepth returns the graph's elements in dependency order, where the ties between
+// simultaneously free elements are broken by their depth, in descending order,
+// and then by the edge list order.
+// The depth of an element is the length of the longest chain of elements, which transitively depend on it;
+// processing the deepest elements first starts the critical path as early as possible.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveByDepth() ([]T, error) {
+	err := dg.Validate()
+	if err != nil {
+		return nil, &ResolveError{
+			Total: len(dg.edges),
+			Err:   fmt.Errorf("validating dependency graph: %w", err),
+		}
+	}
+
+	depths, resolved := dg.depths()
+	if depths == nil {
+		return nil, &ResolveError{
+			Resolved: resolved,
+			Total:    len(dg.edges),
+			Err:      ErrCircularDependency,
+		}
+	}
+
+	return dg.resolveRanked(func(a, b int) bool {
+		if depths[a] != depths[b] {
+			return depths[a] > depths[b]
+		}
+
+		return a < b
+	}), nil
+}
+
+// ResolveStableWith returns the graph's elements in dependency order, where the ties between
+// simultaneously free elements are broken by their position in a previous resolution order,
+// which keeps the result as close as possible to it when the graph changes slightly.
+// Elements, which are not present in previous, go after the ones that are, in the edge list order.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveStableWith(previous []T) ([]T, error) {
+	err := dg.Validate()
+	if err != nil {
+		return nil, &ResolveError{
+			Total: len(dg.edges),
+			Err:   fmt.Errorf("validating dependency graph: %w", err),
+		}
+	}
+
+	if depths, resolved := dg.depths(); depths == nil {
+		return nil, &ResolveError{
+			Resolved: resolved,
+			Total:    len(dg.edges),
+			Err:      ErrCircularDependency,
+		}
+	}
+
+	seen := make(map[T]int, len(previous))
+	for i, name := range previous {
+		if _, ok := seen[name]; !ok {
+			seen[name] = i
+		}
+	}
+
+	ranks := make([]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		rank, ok := seen[edge.name]
+		if !ok {
+			rank = len(previous) + i
+		}
+
+		ranks[i] = rank
+	}
+
+	return dg.resolveRanked(func(a, b int) bool {
+		return ranks[a] < ranks[b]
+	}), nil
+}
+
+// depths computes, for each edge, the length of the longest chain of edges, which transitively depend on it.
+// If the graph has a circular dependency, it returns nil and the number of edges,
+// which can be ordered before reaching the cycle.
+func (dg *DependencyGraph[T]) depths() ([]int, int) {
+	refcounts, dependents := dg.adjacency()
+
+	order := make([]int, 0, len(dg.edges))
+	for i := range dg.edges {
+		if refcounts[i] == 0 {
+			order = append(order, i)
+		}
+	}
+
+	for cur := 0; cur < len(order); cur++ {
+		for _, d := range dependents[order[cur]] {
+			refcounts[d]--
+
+			if refcounts[d] == 0 {
+				order = append(order, d)
+			}
+		}
+	}
+
+	if len(order) != len(dg.edges) {
+		return nil, len(order)
+	}
+
+	// Dependents always come later in the order, so walking it backwards
+	// makes every dependent's depth final before it is used.
+	depths := make([]int, len(dg.edges))
+	for i := len(order) - 1; i >= 0; i-- {
+		for _, d := range dependents[order[i]] {
+			depths[order[i]] = max(depths[order[i]], depths[d]+1)
+		}
+	}
+
+	return depths, 0
+}
+
+// resolveRanked orders the edges of an acyclic graph, always picking the free edge which goes first
+// according to less; edges are referred to by their position in the edge list.
+func (dg *DependencyGraph[T]) resolveRanked(less func(a, b int) bool) []T {
+	refcounts, dependents := dg.adjacency()
+
+	queue := &rankQueue{less: less}
+	for i := range dg.edges {
+		if refcounts[i] == 0 {
+			queue.items = append(queue.items, i)
+		}
+	}
+
+	heap.Init(queue)
+
+	res := make([]T, 0, len(dg.edges))
+
+	for queue.Len() > 0 {
+		cur, _ := heap.Pop(queue).(int)
+		res = append(res, dg.edges[cur].name)
+
+		for _, d := range dependents[cur] {
+			refcounts[d]--
+
+			if refcounts[d] == 0 {
+				heap.Push(queue, d)
+			}
+		}
+	}
+
+	return res
+}
+
+// rankQueue is a heap of edge positions, ordered by an arbitrary comparison function.
+type rankQueue struct {
+	items []int
+	less  func(a, b int) bool
+}
+
+func (q rankQueue) Len() int           { return len(q.items) }
+func (q rankQueue) Less(i, j int) bool { return q.less(q.items[i], q.items[j]) }
+func (q rankQueue) Swap(i, j int)      { q.items[i], q.items[j] = q.items[j], q.items[i] }
+
+func (q *rankQueue) Push(x any) {
+	item, _ := x.(int)
+	q.items = append(q.items, item)
+}
+
+func (q *rankQueue) Pop() any {
+	item := q.items[len(q.items)-1]
+	q.items = q.items[:len(q.items)-1]
+
+	return item
+}
@@ -0,0 +1,84 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestResolveByDepth tests that the deepest free elements are resolved first.
+func TestResolveByDepth(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("lint")
+	dg.Add("fetch")
+	dg.Add("compile", "fetch")
+	dg.Add("link", "compile")
+	dg.Add("package", "link", "lint")
+	dg.Add("docs")
+
+	res, err := dg.ResolveByDepth()
+	if err != nil {
+		t.Fatalf("resolving graph by depth: %v", err)
+	}
+
+	// fetch has a chain of three elements above it, lint has one, docs has none.
+	if !slices.Equal(res, []string{"fetch", "compile", "lint", "link", "package", "docs"}) {
+		t.Fatalf("graph resolved by depth incorrectly: %v", res)
+	}
+
+	dg.Add("fetch", "package")
+
+	var rerr *ResolveError
+
+	_, err = dg.ResolveByDepth()
+	if !errors.As(err, &rerr) || !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("circular dependency not detected: %v", err)
+	}
+
+	if rerr.Resolved != 2 {
+		t.Fatalf("unexpected number of resolved elements: %d", rerr.Resolved)
+	}
+}
+
+// TestResolveStableWith tests that free elements follow their positions in the previous order,
+// and that new elements go last.
+func TestResolveStableWith(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("E")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D")
+
+	res, err := dg.ResolveStableWith([]string{"C", "D", "X", "A", "B", "C"})
+	if err != nil {
+		t.Fatalf("resolving graph with previous order: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"C", "D", "A", "B", "E"}) {
+		t.Fatalf("graph with previous order resolved incorrectly: %v", res)
+	}
+
+	res, err = dg.ResolveStableWith(nil)
+	if err != nil {
+		t.Fatalf("resolving graph without previous order: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "E", "B", "C", "D"}) {
+		t.Fatalf("graph without previous order resolved incorrectly: %v", res)
+	}
+
+	dg.Add("A", "B")
+
+	_, err = dg.ResolveStableWith(res)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved circular graph with previous order: %v", err)
+	}
+
+	dg.Add("F", "Y")
+
+	_, err = dg.ResolveStableWith(res)
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("resolved invalid graph with previous order: %v", err)
+	}
+}
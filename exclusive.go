@@ -0,0 +1,55 @@
+package depgraph
+
+// Exclusive declares a set of mutually exclusive nodes, such as the ones locking the same resource:
+// regardless of their dependencies, no two of them are placed into the same wave by ResolveBatchIter,
+// or are run concurrently by SimulateSchedule; instead, a conflicting node waits for a later wave,
+// or for the conflicting node to finish.
+// The sequential resolution order is not affected.
+// A node may belong to several exclusive sets. Nodes may be declared exclusive before they are added to the graph.
+func (dg *DependencyGraph[T]) Exclusive(nodes ...T) {
+	dg.checkFrozen()
+
+	if len(nodes) < 2 {
+		return
+	}
+
+	if dg.exclusive == nil {
+		dg.exclusive = make(map[T][]int, len(nodes))
+	}
+
+	set := dg.exclusiveSets
+	dg.exclusiveSets++
+
+	for _, node := range nodes {
+		sets := dg.exclusive[node]
+		if len(sets) == 0 || sets[len(sets)-1] != set {
+			dg.exclusive[node] = append(sets, set)
+		}
+	}
+}
+
+// claimExclusive checks whether a node conflicts with any of the exclusive sets, which are in use;
+// if it does not, the node's exclusive sets are marked as used.
+// It reports whether the node may proceed.
+func (dg *DependencyGraph[T]) claimExclusive(name T, used []bool) bool {
+	sets := dg.exclusive[name]
+
+	for _, set := range sets {
+		if used[set] {
+			return false
+		}
+	}
+
+	for _, set := range sets {
+		used[set] = true
+	}
+
+	return true
+}
+
+// releaseExclusive marks a node's exclusive sets as no longer used.
+func (dg *DependencyGraph[T]) releaseExclusive(name T, used []bool) {
+	for _, set := range dg.exclusive[name] {
+		used[set] = false
+	}
+}
@@ -0,0 +1,75 @@
+package depgraph
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestExclusive tests that exclusive nodes never share a wave, and never run concurrently.
+func TestExclusive(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C")
+	dg.Add("D", "A")
+	dg.Exclusive("A", "C")
+	dg.Exclusive("B", "C", "D")
+
+	levels, err := dg.ResolveLevels()
+	if err != nil {
+		t.Fatalf("resolving levels with exclusive nodes: %v", err)
+	}
+
+	if !slices.EqualFunc(levels, [][]string{{"A", "B"}, {"C"}, {"D"}}, slices.Equal) {
+		t.Fatalf("levels with exclusive nodes resolved incorrectly: %v", levels)
+	}
+
+	annotated, err := dg.ResolveAnnotated()
+	if err != nil {
+		t.Fatalf("resolving annotated graph with exclusive nodes: %v", err)
+	}
+
+	for _, node := range annotated {
+		if !slices.Contains(levels[node.Level], node.Node) {
+			t.Fatalf("level of %q is inconsistent with the waves: %d", node.Node, node.Level)
+		}
+	}
+
+	schedule, makespan := dg.SimulateSchedule(4, func(string) time.Duration {
+		return time.Second
+	})
+
+	expected := []ScheduledNode[string]{
+		{Node: "A", Worker: 0, Start: 0, End: time.Second},
+		{Node: "B", Worker: 1, Start: 0, End: time.Second},
+		{Node: "C", Worker: 0, Start: time.Second, End: 2 * time.Second},
+		{Node: "D", Worker: 0, Start: 2 * time.Second, End: 3 * time.Second},
+	}
+
+	if !slices.Equal(schedule, expected) || makespan != 3*time.Second {
+		t.Fatalf("schedule with exclusive nodes computed incorrectly: %v, %v", schedule, makespan)
+	}
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"A", "B", "C", "D"}) {
+		t.Fatalf("exclusive nodes affected the resolution order: %v", res)
+	}
+}
+
+// TestExclusiveSingle tests that a single exclusive node, or none, has no effect.
+func TestExclusiveSingle(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Exclusive("A")
+	dg.Exclusive()
+
+	levels, err := dg.ResolveLevels()
+	if err != nil {
+		t.Fatalf("resolving levels with a single exclusive node: %v", err)
+	}
+
+	if !slices.EqualFunc(levels, [][]string{{"A", "B"}}, slices.Equal) {
+		t.Fatalf("levels with a single exclusive node resolved incorrectly: %v", levels)
+	}
+}
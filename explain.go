@@ -0,0 +1,59 @@
+package depgraph
+
+import "slices"
+
+// Explain performs a dry-run resolution, which does not stop at the first problem.
+// It returns the resolvable prefix of the resolution order, along with a report that maps
+// each node, which cannot be resolved, to its unsatisfied dependencies:
+// the ones that are unknown, or that cannot be resolved themselves due to a cycle,
+// in the order in which they have been added.
+// Unknown dependencies are always reported, regardless of the unknown dependency handler,
+// except for the weak ones, which never block anything.
+// If the graph can be fully resolved, the report is empty.
+// The graph itself is not modified.
+func (dg *DependencyGraph[T]) Explain() ([]T, map[T][]T) {
+	edges := slices.Clone(dg.edges)
+	refcounts := make(map[T]int, len(edges))
+
+	// Account for every dependency, so that unknown ones never get satisfied.
+	// Weak dependencies on absent nodes are the exception, since they never block anything.
+	for _, edge := range edges {
+		for _, dep := range edge.depOrder {
+			if !dg.absentWeak(edge, dep) {
+				refcounts[edge.name]++
+			}
+		}
+	}
+
+	resolved := make(map[T]struct{}, len(edges))
+	prefix := make([]T, 0, len(edges))
+
+	fmax, _ := dg.resolveEdges(edges, refcounts, func(edge *depEdge[T]) bool {
+		resolved[edge.name] = struct{}{}
+		prefix = append(prefix, edge.name)
+
+		return true
+	}, nil)
+
+	blocked := make(map[T][]T, len(edges)-fmax)
+	for _, edge := range edges[fmax:] {
+		for _, dep := range edge.depOrder {
+			if _, ok := resolved[dep]; !ok && !dg.absentWeak(edge, dep) {
+				blocked[edge.name] = append(blocked[edge.name], dep)
+			}
+		}
+	}
+
+	return prefix, blocked
+}
+
+// absentWeak reports whether an element's dependency is a weak one on a node, which is not present in the graph.
+func (dg *DependencyGraph[T]) absentWeak(edge *depEdge[T], dep T) bool {
+	if _, ok := edge.weak[dep]; !ok {
+		return false
+	}
+
+	_, ok := dg.edgeMap[dep]
+
+	return !ok
+}
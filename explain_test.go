@@ -0,0 +1,54 @@
+package depgraph
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestExplain tests that a dry-run resolution reports the unsatisfied dependencies.
+func TestExplain(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A", "C")
+	dg.Add("C", "B")
+	dg.Add("D", "A")
+	dg.Add("E", "X")
+	dg.Add("F", "E", "D")
+
+	prefix, blocked := dg.Explain()
+	if !slices.Equal(prefix, []string{"A", "D"}) {
+		t.Fatalf("resolvable prefix computed incorrectly: %v", prefix)
+	}
+
+	if !maps.EqualFunc(blocked, map[string][]string{"B": {"C"}, "C": {"B"}, "E": {"X"}, "F": {"E"}}, slices.Equal) {
+		t.Fatalf("blocked nodes reported incorrectly: %v", blocked)
+	}
+
+	dg = NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+
+	prefix, blocked = dg.Explain()
+	if !slices.Equal(prefix, []string{"A", "B"}) || len(blocked) != 0 {
+		t.Fatalf("resolvable graph explained incorrectly: %v, %v", prefix, blocked)
+	}
+}
+
+// TestExplainWeak tests that weak dependencies on absent nodes are not reported as blocking.
+func TestExplainWeak(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.AddWeak("A", "X")
+	dg.AddWeak("B", "A")
+	dg.AddWeak("B", "C")
+	dg.Add("C", "B")
+
+	prefix, blocked := dg.Explain()
+	if !slices.Equal(prefix, []string{"A"}) {
+		t.Fatalf("resolvable prefix with weak dependencies computed incorrectly: %v", prefix)
+	}
+
+	if !maps.EqualFunc(blocked, map[string][]string{"B": {"C"}, "C": {"B"}}, slices.Equal) {
+		t.Fatalf("blocked nodes with weak dependencies reported incorrectly: %v", blocked)
+	}
+}
@@ -0,0 +1,280 @@
+package depgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AdjacencyMatrix returns the graph as a boolean adjacency matrix, along with the nodes indexing it,
+// in the edge list order: matrix[i][j] is true when nodes[i] directly depends on nodes[j].
+// Unknown dependencies are not represented in the matrix.
+func (dg *DependencyGraph[T]) AdjacencyMatrix() ([][]bool, []T) {
+	n := len(dg.edges)
+	nodes := make([]T, n)
+	matrix := make([][]bool, n)
+
+	// Back all rows by a single allocation.
+	cells := make([]bool, n*n)
+
+	for i, edge := range dg.edges {
+		nodes[i] = edge.name
+		matrix[i] = cells[i*n : (i+1)*n : (i+1)*n]
+	}
+
+	for i, deps := range dg.depIndices() {
+		for _, j := range deps {
+			matrix[i][j] = true
+		}
+	}
+
+	return matrix, nodes
+}
+
+// ToEdgeList returns the graph's dependencies as a list of (node, dependency) pairs,
+// following the edge list order, and then the order in which the dependencies have been added.
+// Unknown dependencies are included as well, whereas the elements without any dependencies
+// and dependents are not represented at all; see FromEdgeList for the reverse conversion.
+func (dg *DependencyGraph[T]) ToEdgeList() [][2]T {
+	res := [][2]T{}
+
+	for _, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			res = append(res, [2]T{edge.name, dep})
+		}
+	}
+
+	return res
+}
+
+// FromEdgeList creates a new graph from a list of (node, dependency) pairs, such as the one
+// returned by ToEdgeList; both nodes of every pair are added to the graph.
+// The isolated nodes, which have neither dependencies nor dependents, are added after the pairs.
+// The nodes are added in the order of their first occurrence, where a node goes before its dependency.
+func FromEdgeList[T comparable](edges [][2]T, isolated ...T) *DependencyGraph[T] {
+	dg := NewDependencyGraphWithCapacity[T](len(edges) + len(isolated))
+
+	for _, edge := range edges {
+		dg.Add(edge[0], edge[1])
+		dg.Add(edge[1])
+	}
+
+	for _, node := range isolated {
+		dg.Add(node)
+	}
+
+	return dg
+}
+
+// indexNodes assigns a dense index to every node referred to by the graph:
+// graph's elements go first, in the edge list order, followed by the unknown dependencies,
+// in the order of their first occurrence.
+func (dg *DependencyGraph[T]) indexNodes() ([]T, map[T]int) {
+	nodes := make([]T, 0, len(dg.edges))
+	index := make(map[T]int, len(dg.edges))
+
+	for _, edge := range dg.edges {
+		index[edge.name] = len(nodes)
+		nodes = append(nodes, edge.name)
+	}
+
+	for _, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			if _, ok := index[dep]; !ok {
+				index[dep] = len(nodes)
+				nodes = append(nodes, dep)
+			}
+		}
+	}
+
+	return nodes, index
+}
+
+// mermaidEscaper replaces the characters, which may not appear verbatim in a quoted Mermaid label,
+// with their entity codes.
+var mermaidEscaper = strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;")
+
+// WriteMermaid writes the graph into w as a Mermaid flowchart, with an "A --> B" arrow
+// for every dependency of A on B.
+// Every node, including unknown dependencies, is given a generated identifier,
+// which is always valid in Mermaid, while its text, as returned by label, is shown as its label.
+func (dg *DependencyGraph[T]) WriteMermaid(w io.Writer, label func(T) string) error {
+	nodes, index := dg.indexNodes()
+
+	// Errors are sticky in bufio.Writer, so checking the final flush is enough.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph TD")
+
+	for i, node := range nodes {
+		fmt.Fprintf(bw, "    n%d[\"%s\"]\n", i, mermaidEscaper.Replace(label(node)))
+	}
+
+	for _, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			fmt.Fprintf(bw, "    n%d --> n%d\n", index[edge.name], index[dep])
+		}
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("writing mermaid graph: %w", err)
+	}
+
+	return nil
+}
+
+// dotEscaper escapes the characters, which may not appear verbatim in a quoted DOT string.
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteDOT writes the graph into w in the Graphviz DOT format, with an "A -> B" edge
+// for every dependency of A on B.
+// Every node, including unknown dependencies, is given a generated identifier,
+// while its text, as returned by label, is shown as its label.
+func (dg *DependencyGraph[T]) WriteDOT(w io.Writer, label func(T) string) error {
+	nodes, index := dg.indexNodes()
+
+	// Errors are sticky in bufio.Writer, so checking the final flush is enough.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+
+	for i, node := range nodes {
+		fmt.Fprintf(bw, "    n%d [label=\"%s\"];\n", i, dotEscaper.Replace(label(node)))
+	}
+
+	for _, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			fmt.Fprintf(bw, "    n%d -> n%d;\n", index[edge.name], index[dep])
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("writing dot graph: %w", err)
+	}
+
+	return nil
+}
+
+// WriteDOTClustered works like WriteDOT, but wraps the nodes sharing the same value returned by cluster
+// into a Graphviz cluster, which is labeled with that value; the nodes, for which cluster returns
+// an empty string, are not put into any cluster.
+// The clusters are written in the order of their first nodes, and dependencies crossing them
+// are written as usual.
+func (dg *DependencyGraph[T]) WriteDOTClustered(w io.Writer, label func(T) string, cluster func(T) string) error {
+	nodes, index := dg.indexNodes()
+
+	clusters := []string{}
+	members := map[string][]int{}
+
+	for i, node := range nodes {
+		c := cluster(node)
+		if _, ok := members[c]; !ok && c != "" {
+			clusters = append(clusters, c)
+		}
+
+		members[c] = append(members[c], i)
+	}
+
+	// Errors are sticky in bufio.Writer, so checking the final flush is enough.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+
+	for i, c := range clusters {
+		fmt.Fprintf(bw, "    subgraph cluster_%d {\n", i)
+		fmt.Fprintf(bw, "        label=\"%s\";\n", dotEscaper.Replace(c))
+
+		for _, n := range members[c] {
+			fmt.Fprintf(bw, "        n%d [label=\"%s\"];\n", n, dotEscaper.Replace(label(nodes[n])))
+		}
+
+		fmt.Fprintln(bw, "    }")
+	}
+
+	for _, n := range members[""] {
+		fmt.Fprintf(bw, "    n%d [label=\"%s\"];\n", n, dotEscaper.Replace(label(nodes[n])))
+	}
+
+	for _, edge := range dg.edges {
+		for _, dep := range edge.depOrder {
+			fmt.Fprintf(bw, "    n%d -> n%d;\n", index[edge.name], index[dep])
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("writing clustered dot graph: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMakefile writes the graph into w as a Makefile, where every element is a target,
+// whose prerequisites are its dependencies, and whose recipe is the one returned by recipe;
+// a multi-line recipe gets split into several recipe lines, while an empty one produces none.
+// Unknown dependencies are written as prerequisites as well, leaving it up to make to find them,
+// for example as existing files. The targets, as returned by target, are written verbatim,
+// so they must already be valid make targets.
+// The first target is a phony "all" one, which depends on every element,
+// so that running "make -j" processes the whole graph in parallel.
+func (dg *DependencyGraph[T]) WriteMakefile(w io.Writer, target func(T) string, recipe func(T) string) error {
+	// Errors are sticky in bufio.Writer, so checking the final flush is enough.
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, ".PHONY: all")
+	fmt.Fprint(bw, "all:")
+
+	for _, edge := range dg.edges {
+		fmt.Fprintf(bw, " %s", target(edge.name))
+	}
+
+	fmt.Fprintln(bw)
+
+	for _, edge := range dg.edges {
+		fmt.Fprintf(bw, "\n%s:", target(edge.name))
+
+		for _, dep := range edge.depOrder {
+			fmt.Fprintf(bw, " %s", target(dep))
+		}
+
+		fmt.Fprintln(bw)
+
+		if r := recipe(edge.name); r != "" {
+			for _, line := range strings.Split(r, "\n") {
+				fmt.Fprintf(bw, "\t%s\n", line)
+			}
+		}
+	}
+
+	err := bw.Flush()
+	if err != nil {
+		return fmt.Errorf("writing makefile: %w", err)
+	}
+
+	return nil
+}
+
+// WriteSubtreeDOT works like WriteDOT, but only writes a node along with its transitive dependencies.
+// An error is returned if the node is not present in the graph.
+func (dg *DependencyGraph[T]) WriteSubtreeDOT(w io.Writer, root T, label func(T) string) error {
+	if _, ok := dg.edgeMap[root]; !ok {
+		return fmt.Errorf("looking up node \"%v\": %w", root, ErrUnknownNode)
+	}
+
+	subtree := map[T]struct{}{}
+	for _, node := range dg.reachable(root) {
+		subtree[node] = struct{}{}
+	}
+
+	sub := dg.derive(func(name T) bool {
+		_, ok := subtree[name]
+		return ok
+	}, func(_, _ T) bool {
+		return true
+	})
+
+	return sub.WriteDOT(w, label)
+}
@@ -0,0 +1,244 @@
+package depgraph
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestAdjacencyMatrix tests that the adjacency matrix reflects direct dependencies.
+func TestAdjacencyMatrix(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "A", "B", "X")
+	dg.Add("A", "A")
+
+	matrix, nodes := dg.AdjacencyMatrix()
+
+	if !slices.Equal(nodes, []string{"A", "B", "C"}) {
+		t.Fatalf("adjacency matrix nodes listed incorrectly: %v", nodes)
+	}
+
+	expected := [][]bool{
+		{true, false, false},
+		{true, false, false},
+		{true, true, false},
+	}
+
+	if !slices.EqualFunc(matrix, expected, slices.Equal) {
+		t.Fatalf("adjacency matrix computed incorrectly: %v", matrix)
+	}
+}
+
+// TestEdgeList tests that a graph survives being converted into an edge list and back.
+func TestEdgeList(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "lib", "log")
+	dg.Add("lib", "log")
+	dg.Add("log")
+	dg.Add("tool")
+
+	edges := dg.ToEdgeList()
+	if !slices.Equal(edges, [][2]string{{"app", "lib"}, {"app", "log"}, {"lib", "log"}}) {
+		t.Fatalf("edge list computed incorrectly: %v", edges)
+	}
+
+	read := FromEdgeList(edges, "tool")
+	if read.Hash(stringBytes) != dg.Hash(stringBytes) {
+		t.Fatalf("graph has been converted from edge list incorrectly")
+	}
+
+	res, err := read.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph converted from edge list: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"log", "tool", "lib", "app"}) {
+		t.Fatalf("graph converted from edge list resolved incorrectly: %v", res)
+	}
+}
+
+// TestWriteMermaid tests the Mermaid output, including the escaping of labels.
+func TestWriteMermaid(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app server", "db")
+	dg.Add("db", `"disk"`)
+
+	buf := &bytes.Buffer{}
+
+	err := dg.WriteMermaid(buf, func(s string) string { return s })
+	if err != nil {
+		t.Fatalf("writing mermaid graph: %v", err)
+	}
+
+	expected := `graph TD
+    n0["app server"]
+    n1["db"]
+    n2["#quot;disk#quot;"]
+    n0 --> n1
+    n1 --> n2
+`
+
+	if buf.String() != expected {
+		t.Fatalf("mermaid graph written incorrectly:\n%s", buf.String())
+	}
+}
+
+// TestWriteDOT tests the DOT output, including the escaping of labels.
+func TestWriteDOT(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "db")
+	dg.Add("db", `"disk"\`)
+
+	buf := &bytes.Buffer{}
+
+	err := dg.WriteDOT(buf, func(s string) string { return s })
+	if err != nil {
+		t.Fatalf("writing dot graph: %v", err)
+	}
+
+	expected := `digraph {
+    n0 [label="app"];
+    n1 [label="db"];
+    n2 [label="\"disk\"\\"];
+    n0 -> n1;
+    n1 -> n2;
+}
+`
+
+	if buf.String() != expected {
+		t.Fatalf("dot graph written incorrectly:\n%s", buf.String())
+	}
+}
+
+// TestWriteDOTClustered tests that nodes get grouped into clusters, and that edges crossing them are kept.
+func TestWriteDOTClustered(t *testing.T) {
+	teams := map[string]string{"billing": "payments", "ledger": "payments", "auth": `"core"`}
+
+	dg := NewDependencyGraph[string]()
+	dg.Add("billing", "auth", "ledger")
+	dg.Add("auth", "log")
+	dg.Add("ledger")
+
+	buf := &bytes.Buffer{}
+
+	err := dg.WriteDOTClustered(buf, func(s string) string { return s }, func(s string) string { return teams[s] })
+	if err != nil {
+		t.Fatalf("writing clustered dot graph: %v", err)
+	}
+
+	expected := `digraph {
+    subgraph cluster_0 {
+        label="payments";
+        n0 [label="billing"];
+        n2 [label="ledger"];
+    }
+    subgraph cluster_1 {
+        label="\"core\"";
+        n1 [label="auth"];
+    }
+    n3 [label="log"];
+    n0 -> n1;
+    n0 -> n2;
+    n1 -> n3;
+}
+`
+
+	if buf.String() != expected {
+		t.Fatalf("clustered dot graph written incorrectly:\n%s", buf.String())
+	}
+}
+
+// TestWriteMakefile tests the Makefile output, including multi-line and empty recipes.
+func TestWriteMakefile(t *testing.T) {
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		return nil
+	}))
+	dg.Add("app", "lib", "main.c")
+	dg.Add("lib")
+	dg.Add("check", "app")
+
+	recipes := map[string]string{"app": "cc -o app main.c\nstrip app", "lib": "ar rcs lib"}
+
+	buf := &bytes.Buffer{}
+
+	err := dg.WriteMakefile(buf, func(s string) string { return s }, func(s string) string { return recipes[s] })
+	if err != nil {
+		t.Fatalf("writing makefile: %v", err)
+	}
+
+	expected := ".PHONY: all\n" +
+		"all: app lib check\n" +
+		"\napp: lib main.c\n\tcc -o app main.c\n\tstrip app\n" +
+		"\nlib:\n\tar rcs lib\n" +
+		"\ncheck: app\n"
+
+	if buf.String() != expected {
+		t.Fatalf("makefile written incorrectly:\n%s", buf.String())
+	}
+}
+
+// TestWriteSubtreeDOT tests that only the node and its transitive dependencies are written.
+func TestWriteSubtreeDOT(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "api")
+	dg.Add("api", "db", "log")
+	dg.Add("db")
+	dg.Add("tool", "log")
+	dg.Add("log")
+
+	buf := &bytes.Buffer{}
+
+	err := dg.WriteSubtreeDOT(buf, "api", strings.ToUpper)
+	if err != nil {
+		t.Fatalf("writing dot subtree: %v", err)
+	}
+
+	expected := `digraph {
+    n0 [label="API"];
+    n1 [label="DB"];
+    n2 [label="LOG"];
+    n0 -> n1;
+    n0 -> n2;
+}
+`
+
+	if buf.String() != expected {
+		t.Fatalf("dot subtree written incorrectly:\n%s", buf.String())
+	}
+
+	err = dg.WriteSubtreeDOT(buf, "X", strings.ToUpper)
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("wrote dot subtree of an unknown node: %v", err)
+	}
+}
+
+// TestWriteFailures tests that the errors of the underlying writer are reported by every export.
+func TestWriteFailures(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "B")
+	dg.Add("B")
+
+	id := func(s string) string { return s }
+
+	tbl := []struct {
+		name  string
+		write func(w io.Writer) error
+	}{
+		{name: "WriteMermaid", write: func(w io.Writer) error { return dg.WriteMermaid(w, id) }},
+		{name: "WriteDOT", write: func(w io.Writer) error { return dg.WriteDOT(w, id) }},
+		{name: "WriteDOTClustered", write: func(w io.Writer) error { return dg.WriteDOTClustered(w, id, id) }},
+		{name: "WriteMakefile", write: func(w io.Writer) error { return dg.WriteMakefile(w, id, id) }},
+	}
+
+	for _, test := range tbl {
+		err := test.write(&lineWriter{})
+		if err == nil {
+			t.Fatalf("%s: writer error not reported", test.name)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package depgraph
+
+// Freeze makes the graph immutable: any further attempt to mutate it results in a panic.
+// Since a frozen graph never changes, it is safe to resolve and query it concurrently
+// from multiple goroutines without any locking,
+// as long as the callbacks provided through the options are safe for concurrent use as well.
+// A graph cannot be unfrozen; to get a mutable copy, restore its snapshot into another graph.
+func (dg *DependencyGraph[T]) Freeze() {
+	// A frozen graph may be queried concurrently, so the reachability cache cannot be built lazily anymore.
+	if dg.cacheReach && dg.reach == nil {
+		dg.reach = dg.buildReach()
+	}
+
+	dg.frozen = true
+}
+
+// Frozen reports whether the graph has been frozen.
+func (dg *DependencyGraph[T]) Frozen() bool {
+	return dg.frozen
+}
+
+// checkFrozen panics if the graph is frozen.
+func (dg *DependencyGraph[T]) checkFrozen() {
+	if dg.frozen {
+		panic("depgraph: attempt to mutate a frozen dependency graph")
+	}
+}
+
+// mutate prepares the graph for a mutation.
+func (dg *DependencyGraph[T]) mutate() {
+	dg.checkFrozen()
+	dg.own()
+	dg.validated = false
+	dg.reach = nil
+	dg.generation++
+}
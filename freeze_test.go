@@ -0,0 +1,88 @@
+package depgraph
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestFreeze tests that a frozen graph cannot be mutated,
+// and that it can be mutated again after being restored into another graph.
+func TestFreeze(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("B", "A")
+	dg.Add("A")
+	dg.Freeze()
+
+	if !dg.Frozen() {
+		t.Fatalf("graph is not frozen")
+	}
+
+	mutators := map[string]func(){
+		"Add":     func() { dg.Add("C") },
+		"Clear":   func() { dg.Clear() },
+		"Restore": func() { dg.Restore(Snapshot[string]{}) },
+	}
+
+	for name, mutate := range mutators {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("frozen graph mutated by %s", name)
+				}
+			}()
+
+			mutate()
+		}()
+	}
+
+	other := NewDependencyGraph[string]()
+	other.Restore(dg.Snapshot())
+	other.Add("C", "B")
+
+	res, err := other.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph restored from a frozen one: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B", "C"}) {
+		t.Fatalf("graph restored from a frozen one resolved incorrectly: %v", res)
+	}
+
+	if !slices.Equal(dg.edges, []*depEdge[string]{dg.edgeMap["B"], dg.edgeMap["A"]}) {
+		t.Fatalf("frozen graph has been modified")
+	}
+}
+
+// TestFreezeConcurrent tests that a frozen graph may be resolved concurrently;
+// it is intended to be run with the race detector.
+func TestFreezeConcurrent(t *testing.T) {
+	dg := NewDependencyGraph[int]()
+	for i := range 99 {
+		dg.Add(i, i+1)
+	}
+
+	dg.Add(99)
+	dg.Freeze()
+
+	expected, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving frozen graph: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			res, err := dg.Resolve()
+			if err != nil || !slices.Equal(res, expected) {
+				t.Errorf("frozen graph resolved concurrently incorrectly: %v, %v", res, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
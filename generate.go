@@ -0,0 +1,32 @@
+package depgraph
+
+import "math/rand/v2"
+
+// GenerateRandomDAG builds a random acyclic graph of n nodes, named by the name function,
+// which is useful for testing and benchmarking code built on top of this package.
+// The nodes are added in the order of their indices, and are randomly ranked:
+// every node depends on each lower-ranked node with the probability p, so that the graph is
+// guaranteed to be acyclic, while its resolution order differs from the insertion order.
+// The same seed always produces the same graph.
+func GenerateRandomDAG[T comparable](n int, p float64, seed uint64, name func(i int) T) *DependencyGraph[T] {
+	//nolint:gosec // The graph is generated for testing purposes, so a weak generator suffices.
+	rng := rand.New(rand.NewPCG(seed, seed))
+	rank := rng.Perm(n)
+
+	dg := NewDependencyGraphWithCapacity[T](n)
+	deps := []T{}
+
+	for i := range n {
+		deps = deps[:0]
+
+		for j := range n {
+			if rank[j] < rank[i] && rng.Float64() < p {
+				deps = append(deps, name(j))
+			}
+		}
+
+		dg.Add(name(i), deps...)
+	}
+
+	return dg
+}
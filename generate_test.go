@@ -0,0 +1,89 @@
+package depgraph
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestGenerateRandomDAG tests that generated graphs are acyclic and reproducible.
+func TestGenerateRandomDAG(t *testing.T) {
+	name := func(i int) int {
+		return i
+	}
+
+	a := GenerateRandomDAG(200, 0.05, 42, name)
+	b := GenerateRandomDAG(200, 0.05, 42, name)
+
+	if a.Hash(intBytes) != b.Hash(intBytes) {
+		t.Fatalf("graphs generated with the same seed differ")
+	}
+
+	res, err := a.Resolve()
+	if err != nil {
+		t.Fatalf("resolving generated graph: %v", err)
+	}
+
+	if len(res) != 200 {
+		t.Fatalf("generated graph has an unexpected size: %d", len(res))
+	}
+}
+
+// intBytes encodes an integer for hashing.
+func intBytes(i int) []byte {
+	return fmt.Appendf(nil, "%d", i)
+}
+
+// BenchmarkResolve measures the resolution of random graphs of varying size and density.
+func BenchmarkResolve(b *testing.B) {
+	for _, n := range []int{100, 500, 2500} {
+		for _, p := range []float64{0.001, 0.01, 0.05} {
+			b.Run(fmt.Sprintf("n=%d/p=%g", n, p), func(b *testing.B) {
+				dg := GenerateRandomDAG(n, p, 1, func(i int) int {
+					return i
+				})
+
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for range b.N {
+					_, err := dg.Resolve()
+					if err != nil {
+						b.Fatalf("resolving generated graph: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkResolveInPlace compares the cost of resolving on a copy of the edge list,
+// which is the default, to resolving in-place.
+func BenchmarkResolveInPlace(b *testing.B) {
+	for _, inPlace := range []bool{false, true} {
+		b.Run(fmt.Sprintf("inplace=%t", inPlace), func(b *testing.B) {
+			opts := []Option[int]{}
+			if inPlace {
+				opts = append(opts, WithInPlaceResolve[int]())
+			}
+
+			dg := NewDependencyGraph(opts...)
+			src := GenerateRandomDAG(1000, 0.01, 1, func(i int) int {
+				return i
+			})
+
+			for _, edge := range src.edges {
+				dg.Add(edge.name, edge.depOrder...)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for range b.N {
+				_, err := dg.Resolve()
+				if err != nil {
+					b.Fatalf("resolving generated graph: %v", err)
+				}
+			}
+		})
+	}
+}
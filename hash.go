@@ -0,0 +1,104 @@
+package depgraph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+)
+
+// Hash computes a fingerprint of the graph's structure: its nodes and their dependencies.
+// Each node is encoded with h; the encodings are then hashed in a canonical order,
+// so that two structurally equal graphs produce the same hash regardless of the insertion order.
+// h must encode distinct nodes differently, otherwise they are indistinguishable for the hash.
+// Since the insertion order is not taken into account, graphs with equal hashes
+// may still be resolved in a different order.
+func (dg *DependencyGraph[T]) Hash(h func(T) []byte) [32]byte {
+	type record struct {
+		node []byte
+		deps [][]byte
+	}
+
+	records := make([]record, 0, len(dg.edges))
+	for _, edge := range dg.edges {
+		rec := record{
+			node: h(edge.name),
+			deps: make([][]byte, 0, len(edge.deps)),
+		}
+
+		for _, dep := range edge.depOrder {
+			rec.deps = append(rec.deps, h(dep))
+		}
+
+		slices.SortFunc(rec.deps, bytes.Compare)
+		records = append(records, rec)
+	}
+
+	slices.SortFunc(records, func(a, b record) int {
+		return bytes.Compare(a.node, b.node)
+	})
+
+	// Every variable-length part is prefixed with its length, so that the encoding is unambiguous.
+	buf := binary.AppendUvarint(nil, uint64(len(records)))
+	for _, rec := range records {
+		buf = binary.AppendUvarint(buf, uint64(len(rec.node)))
+		buf = append(buf, rec.node...)
+		buf = binary.AppendUvarint(buf, uint64(len(rec.deps)))
+
+		for _, dep := range rec.deps {
+			buf = binary.AppendUvarint(buf, uint64(len(dep)))
+			buf = append(buf, dep...)
+		}
+	}
+
+	return sha256.Sum256(buf)
+}
+
+// NodeSignatures computes a signature of every element, which captures both its own content,
+// as encoded by contentHash, and the signatures of its dependencies, so that a change of any
+// transitive dependency changes the signature as well; it may serve as a content-addressed cache key.
+// The signatures of the dependencies are combined in a canonical order, so they do not depend
+// on the order, in which the dependencies have been added.
+// Unknown dependencies, which have survived the validation, are signed by their content alone.
+// The elements are signed in dependency order, so the errors are the same as the ones of Resolve.
+func (dg *DependencyGraph[T]) NodeSignatures(contentHash func(T) []byte) (map[T][]byte, error) {
+	order, err := dg.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	sign := func(content []byte, deps [][]byte) []byte {
+		slices.SortFunc(deps, bytes.Compare)
+
+		// The content is prefixed with its length, while the signatures have a fixed one.
+		buf := binary.AppendUvarint(nil, uint64(len(content)))
+		buf = append(buf, content...)
+
+		for _, dep := range deps {
+			buf = append(buf, dep...)
+		}
+
+		sum := sha256.Sum256(buf)
+
+		return sum[:]
+	}
+
+	res := make(map[T][]byte, len(order))
+	for _, name := range order {
+		edge := dg.edgeMap[name]
+		deps := make([][]byte, 0, len(edge.depOrder))
+
+		for _, dep := range edge.depOrder {
+			sig, ok := res[dep]
+			if !ok {
+				sig = sign(contentHash(dep), nil)
+			}
+
+			deps = append(deps, sig)
+		}
+
+		res[name] = sign(contentHash(name), deps)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,141 @@
+package depgraph
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// TestHash tests that graph hashes depend only on the graph's structure.
+func TestHash(t *testing.T) {
+	enc := func(s string) []byte {
+		return []byte(s)
+	}
+
+	a := NewDependencyGraph[string]()
+	a.Add("A")
+	a.Add("B", "A")
+	a.Add("C", "A", "B")
+
+	b := NewDependencyGraph[string]()
+	b.Add("C", "B")
+	b.Add("B", "A")
+	b.Add("A")
+	b.Add("C", "A")
+
+	if a.Hash(enc) != b.Hash(enc) {
+		t.Fatalf("structurally equal graphs have different hashes")
+	}
+
+	b.Add("D")
+	if a.Hash(enc) == b.Hash(enc) {
+		t.Fatalf("graphs with different nodes have equal hashes")
+	}
+
+	a.Add("D", "C")
+	if a.Hash(enc) == b.Hash(enc) {
+		t.Fatalf("graphs with different edges have equal hashes")
+	}
+
+	// Moving a dependency from one node to another must change the hash.
+	c := NewDependencyGraph[string]()
+	c.Add("AB")
+	c.Add("C", "AB")
+
+	d := NewDependencyGraph[string]()
+	d.Add("AB", "C")
+	d.Add("C")
+
+	if c.Hash(enc) == d.Hash(enc) {
+		t.Fatalf("graphs with reversed edges have equal hashes")
+	}
+}
+
+// TestNodeSignatures tests that a change of the content propagates to the signatures of the dependents only,
+// and that the order of dependencies does not matter.
+func TestNodeSignatures(t *testing.T) {
+	content := map[string]string{"app": "main", "lib": "v1", "util": "v1", "tool": "v1"}
+	hash := func(s string) []byte {
+		return []byte(content[s])
+	}
+
+	build := func(deps ...string) *DependencyGraph[string] {
+		dg := NewDependencyGraph[string]()
+		dg.Add("app", deps...)
+		dg.Add("lib", "util")
+		dg.Add("util")
+		dg.Add("tool", "util")
+
+		return dg
+	}
+
+	dg := build("lib", "tool")
+
+	before, err := dg.NodeSignatures(hash)
+	if err != nil {
+		t.Fatalf("computing signatures: %v", err)
+	}
+
+	reordered, err := build("tool", "lib").NodeSignatures(hash)
+	if err != nil {
+		t.Fatalf("computing signatures of reordered graph: %v", err)
+	}
+
+	if !bytes.Equal(before["app"], reordered["app"]) {
+		t.Fatalf("signature depends on the order of dependencies")
+	}
+
+	content["lib"] = "v2"
+
+	after, err := dg.NodeSignatures(hash)
+	if err != nil {
+		t.Fatalf("computing signatures after a change: %v", err)
+	}
+
+	for node, changed := range map[string]bool{"app": true, "lib": true, "util": false, "tool": false} {
+		if bytes.Equal(before[node], after[node]) == changed {
+			t.Fatalf("signature of %v changed incorrectly; expected a change = %v", node, changed)
+		}
+	}
+
+	dg.Add("util", "app")
+
+	_, err = dg.NodeSignatures(hash)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("computed signatures of a graph with circular dependency: %v", err)
+	}
+}
+
+// TestNodeSignaturesUnknown tests that unknown dependencies are signed by their content alone.
+func TestNodeSignaturesUnknown(t *testing.T) {
+	content := map[string]string{"app": "main", "lib": "v1", "ext": "v2"}
+	hash := func(s string) []byte {
+		return []byte(content[s])
+	}
+
+	build := func() *DependencyGraph[string] {
+		return NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+			return nil
+		}))
+	}
+
+	dg := build()
+	dg.Add("app", "lib")
+	dg.Add("lib", "ext")
+
+	before, err := dg.NodeSignatures(hash)
+	if err != nil {
+		t.Fatalf("computing signatures with unknown dependency: %v", err)
+	}
+
+	content["ext"] = "v3"
+
+	after, err := dg.NodeSignatures(hash)
+	if err != nil {
+		t.Fatalf("computing signatures with unknown dependency: %v", err)
+	}
+
+	if bytes.Equal(before["app"], after["app"]) || bytes.Equal(before["lib"], after["lib"]) {
+		t.Fatalf("change of unknown dependency not propagated to signatures")
+	}
+}
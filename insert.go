@@ -0,0 +1,119 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AddBefore works like Add, but instead of appending the element to the end of the edge list,
+// it places the element right before the existing one.
+// This only affects the tie-break ordering between elements, not their dependencies.
+// If the element has already been added, its dependencies get concatenated together,
+// and it is moved to the new position.
+// An error is returned if the existing element is not present in the graph.
+func (dg *DependencyGraph[T]) AddBefore(existing, name T, deps ...T) error {
+	return dg.addAt(existing, name, false, deps)
+}
+
+// AddAfter works like Add, but instead of appending the element to the end of the edge list,
+// it places the element right after the existing one.
+// This only affects the tie-break ordering between elements, not their dependencies.
+// If the element has already been added, its dependencies get concatenated together,
+// and it is moved to the new position.
+// An error is returned if the existing element is not present in the graph.
+func (dg *DependencyGraph[T]) AddAfter(existing, name T, deps ...T) error {
+	return dg.addAt(existing, name, true, deps)
+}
+
+// addAt adds an element next to the existing one, either before or after it.
+func (dg *DependencyGraph[T]) addAt(existing, name T, after bool, deps []T) error {
+	if len(dg.aliases) > 0 {
+		existing, name = dg.resolveAlias(existing), dg.resolveAlias(name)
+	}
+
+	if _, ok := dg.edgeMap[existing]; !ok {
+		return fmt.Errorf("looking up node \"%v\": %w", existing, ErrUnknownNode)
+	}
+
+	dg.Add(name, deps...)
+
+	if name == existing {
+		return nil
+	}
+
+	// Detach the element from its current position and splice it next to the existing one.
+	// The edge list is rebuilt rather than modified in-place, so that a resolution in progress,
+	// which may be reordering the same list, is not affected.
+	edge := dg.edgeMap[name]
+	dg.edges = slices.DeleteFunc(slices.Clone(dg.edges), func(e *depEdge[T]) bool {
+		return e == edge
+	})
+
+	pos := slices.Index(dg.edges, dg.edgeMap[existing])
+	if after {
+		pos++
+	}
+
+	dg.edges = slices.Insert(dg.edges, pos, edge)
+
+	return nil
+}
+
+// AddValidated works like Add, but first checks the new dependencies, and reports the first bad one
+// instead of adding anything.
+// A dependency is bad if it would introduce a circular dependency, or if it is not known to the graph
+// and the unknown dependency handler, if there is one, does not accept it.
+// Only the new dependencies are checked, so this is much cheaper than validating
+// or resolving the whole graph after every change.
+func (dg *DependencyGraph[T]) AddValidated(name T, deps ...T) error {
+	if len(dg.aliases) > 0 {
+		name, deps = dg.canonicalize(name, deps)
+	}
+
+	for _, dep := range deps {
+		if dg.WouldCycle(name, dep) {
+			return fmt.Errorf("adding dependency \"%v\" of \"%v\": %w", dep, name, ErrCircularDependency)
+		}
+
+		if _, ok := dg.edgeMap[dep]; ok {
+			continue
+		}
+
+		if dg.unknownHandler == nil {
+			return fmt.Errorf("adding dependency \"%v\" of \"%v\": %w", dep, name, ErrUnknownDependency)
+		}
+
+		err := dg.unknownHandler(name, dep)
+		if err != nil {
+			return fmt.Errorf("handling unknown dependency \"%v\" of \"%v\": %w", dep, name, err)
+		}
+	}
+
+	dg.Add(name, deps...)
+
+	return nil
+}
+
+// Item is an element of the graph along with its dependencies, as accepted by AddAll.
+type Item[T comparable] struct {
+	Name T
+	Deps []T
+}
+
+// AddAll adds several elements, along with their dependencies, in the order of the items,
+// just as if Add was called for each of them in turn.
+func (dg *DependencyGraph[T]) AddAll(items []Item[T]) {
+	for _, item := range items {
+		dg.Add(item.Name, item.Deps...)
+	}
+}
+
+// AddAllFunc adds several elements of arbitrary items to a graph, in the order of the items:
+// for every item, node returns the element and its dependencies, which are then added as with Add.
+// It saves converting the items, such as decoded configuration entries, into Item values first.
+func AddAllFunc[S any, T comparable](dg *DependencyGraph[T], items []S, node func(S) (T, []T)) {
+	for _, item := range items {
+		name, deps := node(item)
+		dg.Add(name, deps...)
+	}
+}
@@ -0,0 +1,193 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestAddBeforeAfter tests that positional insertion affects the tie-break ordering.
+func TestAddBeforeAfter(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C", "A")
+
+	err := dg.AddBefore("A", "D")
+	if err != nil {
+		t.Fatalf("adding element before another one: %v", err)
+	}
+
+	err = dg.AddAfter("A", "E", "B")
+	if err != nil {
+		t.Fatalf("adding element after another one: %v", err)
+	}
+
+	// Move an existing element.
+	err = dg.AddAfter("D", "B")
+	if err != nil {
+		t.Fatalf("moving element after another one: %v", err)
+	}
+
+	err = dg.AddBefore("C", "C", "D")
+	if err != nil {
+		t.Fatalf("adding element before itself: %v", err)
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with positional insertion: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"D", "B", "A", "E", "C"}) {
+		t.Fatalf("graph with positional insertion resolved incorrectly: %v", res)
+	}
+
+	err = dg.AddBefore("X", "F")
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("added element before an unknown one: %v", err)
+	}
+}
+
+// TestAddBeforeAfterAlias tests that both the existing and the new element get resolved through aliases.
+func TestAddBeforeAfterAlias(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+
+	for _, alias := range [][2]string{{"A", "a"}, {"B", "b"}, {"C", "c"}} {
+		err := dg.Alias(alias[0], alias[1])
+		if err != nil {
+			t.Fatalf("adding alias: %v", err)
+		}
+	}
+
+	err := dg.AddBefore("a", "c")
+	if err != nil {
+		t.Fatalf("adding element before aliased one: %v", err)
+	}
+
+	err = dg.AddAfter("c", "b")
+	if err != nil {
+		t.Fatalf("adding element after aliased one: %v", err)
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with aliased elements: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"C", "B", "A"}) {
+		t.Fatalf("aliased elements placed incorrectly: %v", res)
+	}
+}
+
+// TestAddValidated tests that bad dependencies are reported without modifying the graph.
+func TestAddValidated(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+
+	err := dg.AddValidated("A")
+	if err != nil {
+		t.Fatalf("adding element without dependencies: %v", err)
+	}
+
+	err = dg.AddValidated("B", "A")
+	if err != nil {
+		t.Fatalf("adding element with a known dependency: %v", err)
+	}
+
+	err = dg.AddValidated("A", "B")
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("circular dependency not reported: %v", err)
+	}
+
+	err = dg.AddValidated("C", "C")
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("dependency on itself not reported: %v", err)
+	}
+
+	err = dg.AddValidated("C", "A", "X")
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("unknown dependency not reported: %v", err)
+	}
+
+	if _, ok := dg.edgeMap["C"]; ok {
+		t.Fatalf("element with a bad dependency has been added")
+	}
+
+	if deps := dg.Dependencies("A"); len(deps) != 0 {
+		t.Fatalf("bad dependency has been added: %v", deps)
+	}
+}
+
+// TestAddValidatedHandler tests that unknown dependencies are left to the unknown dependency handler,
+// and that aliases are resolved before checking the dependencies.
+func TestAddValidatedHandler(t *testing.T) {
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, dep string) error {
+		if dep == "X" {
+			return nil
+		}
+
+		return errors.New("not allowed")
+	}))
+	dg.Add("A")
+
+	err := dg.Alias("A", "a")
+	if err != nil {
+		t.Fatalf("aliasing node: %v", err)
+	}
+
+	err = dg.AddValidated("B", "a", "X")
+	if err != nil {
+		t.Fatalf("adding element with an accepted unknown dependency: %v", err)
+	}
+
+	if deps := dg.Dependencies("B"); !slices.Equal(deps, []string{"A", "X"}) {
+		t.Fatalf("validated dependencies added incorrectly: %v", deps)
+	}
+
+	err = dg.AddValidated("a", "B")
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("circular dependency through an alias not reported: %v", err)
+	}
+
+	err = dg.AddValidated("C", "Y")
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("rejected unknown dependency not reported: %v", err)
+	}
+}
+
+// TestAddAll tests that the items are added in their order, both directly and through an accessor.
+func TestAddAll(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.AddAll([]Item[string]{
+		{Name: "B", Deps: []string{"A"}},
+		{Name: "A"},
+		{Name: "C"},
+		{Name: "B", Deps: []string{"C"}},
+	})
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"A", "C", "B"}) {
+		t.Fatalf("graph with items resolved incorrectly: %v", res)
+	}
+
+	type service struct {
+		ID       string
+		Requires []string
+	}
+
+	services := []service{
+		{ID: "api", Requires: []string{"db"}},
+		{ID: "db"},
+	}
+
+	sg := NewDependencyGraph[string]()
+	AddAllFunc(sg, services, func(s service) (string, []string) {
+		return s.ID, s.Requires
+	})
+
+	if res := sg.MustResolve(); !slices.Equal(res, []string{"db", "api"}) {
+		t.Fatalf("graph with accessed items resolved incorrectly: %v", res)
+	}
+}
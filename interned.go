@@ -0,0 +1,155 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+)
+
+// InternedGraph is a memory-efficient variant of DependencyGraph, intended for very large graphs.
+// Every node gets interned into a dense integer id, and the resolution works over plain integer
+// adjacency arrays instead of maps of pointers, which greatly reduces allocations
+// and improves the cache locality, at the cost of an interning pass.
+// Given the same sequence of Add calls, it resolves into exactly the same order as DependencyGraph.
+type InternedGraph[T comparable] struct {
+	ids   map[T]int
+	names []T
+	deps  [][]int
+	added []bool
+	order []int
+}
+
+// NewInternedGraph creates a new memory-efficient stable dependency graph.
+func NewInternedGraph[T comparable]() *InternedGraph[T] {
+	return &InternedGraph[T]{
+		ids: map[T]int{},
+	}
+}
+
+// Intern returns the dense integer id of a node, assigning a new one if the node has not been seen yet.
+// Interning a node does not add it to the graph; it merely reserves an id for it.
+func (g *InternedGraph[T]) Intern(name T) int {
+	if id, ok := g.ids[name]; ok {
+		return id
+	}
+
+	id := len(g.names)
+	g.ids[name] = id
+	g.names = append(g.names, name)
+	g.deps = append(g.deps, nil)
+	g.added = append(g.added, false)
+
+	return id
+}
+
+// Add adds an element to the end of the graph's edge list.
+// Just like DependencyGraph.Add, it may be called multiple times for the same element,
+// in which case its dependencies get concatenated together.
+func (g *InternedGraph[T]) Add(name T, deps ...T) {
+	id := g.Intern(name)
+	if !g.added[id] {
+		g.added[id] = true
+		g.order = append(g.order, id)
+	}
+
+	for _, dep := range deps {
+		g.deps[id] = append(g.deps[id], g.Intern(dep))
+	}
+}
+
+// Resolve returns the graph's elements in dependency order.
+// The errors are the same as the ones returned by DependencyGraph.Resolve.
+func (g *InternedGraph[T]) Resolve() ([]T, error) {
+	n := len(g.order)
+	refcounts := make([]int, len(g.names))
+	start := make([]int, len(g.names)+1)
+
+	// Deduplicate the dependencies and check that all of them are known.
+	for _, id := range g.order {
+		slices.Sort(g.deps[id])
+		g.deps[id] = slices.Compact(g.deps[id])
+
+		for _, dep := range g.deps[id] {
+			if !g.added[dep] {
+				return nil, &ResolveError{
+					Total: n,
+					Err:   fmt.Errorf("validating dependency graph: looking up dependency \"%v\": %w", g.names[dep], ErrUnknownDependency),
+				}
+			}
+
+			refcounts[id]++
+			start[dep+1]++
+		}
+	}
+
+	// Build a compact list of dependents for each node,
+	// where dependents[start[id]:start[id+1]] are the dependents of id.
+	for i := range len(g.names) {
+		start[i+1] += start[i]
+	}
+
+	fill := slices.Clone(start[:len(g.names)])
+	dependents := make([]int, start[len(g.names)])
+
+	for _, id := range g.order {
+		for _, dep := range g.deps[id] {
+			dependents[fill[dep]] = id
+			fill[dep]++
+		}
+	}
+
+	edges := slices.Clone(g.order)
+	pos := make([]int, len(g.names))
+
+	for i, id := range edges {
+		pos[id] = i
+	}
+
+	swap := func(i, j int) {
+		edges[i], edges[j] = edges[j], edges[i]
+		pos[edges[i]] = i
+		pos[edges[j]] = j
+	}
+
+	// Promote all free edges to the start of the edge list, exactly as DependencyGraph does.
+	fmax := 0
+	for i := range edges {
+		if refcounts[edges[i]] == 0 {
+			swap(fmax, i)
+			fmax++
+		}
+	}
+
+	res := make([]T, 0, n)
+	scratch := []int{}
+
+	for fcur := 0; fcur < fmax; fcur++ {
+		this := edges[fcur]
+		res = append(res, g.names[this])
+
+		// DependencyGraph scans the later edges in their current order,
+		// so process the dependents in the same order to get an identical result.
+		scratch = append(scratch[:0], dependents[start[this]:start[this+1]]...)
+		slices.SortFunc(scratch, func(a, b int) int {
+			return pos[a] - pos[b]
+		})
+
+		for _, dep := range scratch {
+			refcounts[dep]--
+
+			if refcounts[dep] == 0 {
+				swap(fmax, pos[dep])
+				fmax++
+			}
+		}
+	}
+
+	if fmax != n {
+		return nil, &ResolveError{
+			Resolved: fmax,
+			Total:    n,
+			Err:      ErrCircularDependency,
+		}
+	}
+
+	return res, nil
+}
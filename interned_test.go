@@ -0,0 +1,73 @@
+package depgraph
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// TestInternedGraph tests that the interned graph resolves exactly like a regular one,
+// by comparing their results on random graphs.
+func TestInternedGraph(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	for range 1000 {
+		dg := NewDependencyGraph[int]()
+		ig := NewInternedGraph[int]()
+
+		n := rng.IntN(20)
+		for range rng.IntN(40) {
+			name := rng.IntN(n + 1)
+			deps := []int{}
+
+			for range rng.IntN(3) {
+				// Mostly refer to earlier nodes, so that most graphs end up being acyclic.
+				if dep := rng.IntN(n + 2); dep != name || rng.IntN(10) == 0 {
+					deps = append(deps, dep)
+				}
+			}
+
+			dg.Add(name, deps...)
+			ig.Add(name, deps...)
+		}
+
+		expected, expectedErr := dg.Resolve()
+		res, err := ig.Resolve()
+
+		if !slices.Equal(res, expected) {
+			t.Fatalf("interned graph resolved differently: output = %v; expected = %v", res, expected)
+		}
+
+		if errors.Is(err, ErrCircularDependency) != errors.Is(expectedErr, ErrCircularDependency) ||
+			errors.Is(err, ErrUnknownDependency) != errors.Is(expectedErr, ErrUnknownDependency) {
+			t.Fatalf("interned graph failed differently: error = %v; expected = %v", err, expectedErr)
+		}
+	}
+}
+
+// TestInternedGraphIntern tests that ids are dense and stable.
+func TestInternedGraphIntern(t *testing.T) {
+	ig := NewInternedGraph[string]()
+	ig.Add("B", "A")
+
+	if ig.Intern("B") != 0 || ig.Intern("A") != 1 || ig.Intern("C") != 2 {
+		t.Fatalf("unexpected interned ids")
+	}
+
+	_, err := ig.Resolve()
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("resolved interned graph with unknown dependency: %v", err)
+	}
+
+	ig.Add("A")
+
+	res, err := ig.Resolve()
+	if err != nil {
+		t.Fatalf("resolving interned graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("interned graph resolved incorrectly: %v", res)
+	}
+}
@@ -0,0 +1,96 @@
+package depgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// LoadEdges reads edges from r line by line, adding each of them to the graph as soon as it is parsed,
+// so that arbitrarily large inputs may be consumed without holding them in memory.
+// Every line, with its line ending stripped, is passed to parse, which should return the node
+// and, if hasDep is true, its dependency; otherwise, the node is registered without any dependencies.
+// If parse returns an error, loading stops and the error is returned, wrapped along with the line number.
+// Edges which have been loaded before an error occurred remain in the graph.
+func (dg *DependencyGraph[T]) LoadEdges(r io.Reader, parse func(line string) (node, dep T, hasDep bool, err error)) error {
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+
+		node, dep, hasDep, err := parse(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("parsing line %d: %w", lineNo, err)
+		}
+
+		if hasDep {
+			dg.Add(node, dep)
+		} else {
+			dg.Add(node)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return fmt.Errorf("reading edges: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveToWriter streams the resolution order into w, writing every element as a separate line,
+// as returned by format, as soon as it gets resolved.
+// Every line is written with a separate call, so that a downstream consumer may start processing
+// the output right away; w should be buffered by the caller if that is not desired.
+// If the graph cannot be resolved, the lines written so far form a valid prefix of the resolution order,
+// and a *ResolveError is returned.
+func (dg *DependencyGraph[T]) ResolveToWriter(w io.Writer, format func(T) string) error {
+	for el, err := range dg.ResolveIter() {
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, format(el)+"\n")
+		if err != nil {
+			return fmt.Errorf("writing resolved element \"%v\": %w", el, err)
+		}
+	}
+
+	return nil
+}
+
+// WriteWaves streams the graph's waves, as yielded by ResolveBatchIter, into w:
+// every element is written as a separate line, as returned by format, and the waves are separated
+// by a barrier line, which consists of sep, such as "wait" in a generated shell script.
+// Every wave is written as soon as it gets resolved.
+// If the graph cannot be resolved, the waves written so far are a valid prefix of the schedule,
+// and a *ResolveError is returned.
+func (dg *DependencyGraph[T]) WriteWaves(w io.Writer, sep string, format func(T) string) error {
+	bw := bufio.NewWriter(w)
+	first := true
+
+	for batch, err := range dg.ResolveBatchIter() {
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			fmt.Fprintln(bw, sep)
+		}
+
+		first = false
+
+		for _, el := range batch {
+			fmt.Fprintln(bw, format(el))
+		}
+
+		// Errors are sticky in bufio.Writer, so they surface here for every wave.
+		err = bw.Flush()
+		if err != nil {
+			return fmt.Errorf("writing waves: %w", err)
+		}
+	}
+
+	return nil
+}
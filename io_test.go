@@ -0,0 +1,150 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// parseEdgeLine parses lines formatted as either "node" or "node dep".
+func parseEdgeLine(line string) (string, string, bool, error) {
+	fields := strings.Fields(line)
+
+	switch len(fields) {
+	case 1:
+		return fields[0], "", false, nil
+	case 2:
+		return fields[0], fields[1], true, nil
+	default:
+		return "", "", false, errors.New("malformed line")
+	}
+}
+
+// TestLoadEdges tests that edges get loaded from a reader line by line.
+func TestLoadEdges(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+
+	err := dg.LoadEdges(strings.NewReader("A\nB A\r\nC\nD B\nD A\nE"), parseEdgeLine)
+	if err != nil {
+		t.Fatalf("loading edges: %v", err)
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving loaded graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "C", "E", "B", "D"}) {
+		t.Fatalf("loaded graph resolved incorrectly: %v", res)
+	}
+}
+
+// TestLoadEdgesMalformed tests that a parsing error stops the loading.
+func TestLoadEdgesMalformed(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+
+	err := dg.LoadEdges(strings.NewReader("A\nB A\nC D E\nF"), parseEdgeLine)
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("loaded malformed edges: %v", err)
+	}
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving partially loaded graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("partially loaded graph resolved incorrectly: %v", res)
+	}
+
+	err = dg.LoadEdges(iotest.ErrReader(errors.New("broken pipe")), parseEdgeLine)
+	if err == nil || !strings.Contains(err.Error(), "broken pipe") {
+		t.Fatalf("reader error not reported when loading edges: %v", err)
+	}
+}
+
+// lineWriter records every call to Write separately, failing after a given number of calls.
+type lineWriter struct {
+	lines []string
+	limit int
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	if len(w.lines) == w.limit {
+		return 0, errors.New("writer is full")
+	}
+
+	w.lines = append(w.lines, string(p))
+
+	return len(p), nil
+}
+
+// TestResolveToWriter tests that the resolution order is streamed line by line.
+func TestResolveToWriter(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("B", "A")
+	dg.Add("A")
+	dg.Add("C", "B")
+
+	w := &lineWriter{limit: 10}
+
+	err := dg.ResolveToWriter(w, strings.ToLower)
+	if err != nil {
+		t.Fatalf("resolving graph to writer: %v", err)
+	}
+
+	if !slices.Equal(w.lines, []string{"a\n", "b\n", "c\n"}) {
+		t.Fatalf("graph resolved to writer incorrectly: %q", w.lines)
+	}
+
+	w = &lineWriter{limit: 1}
+
+	err = dg.ResolveToWriter(w, strings.ToLower)
+	if err == nil || !strings.Contains(err.Error(), "writer is full") {
+		t.Fatalf("writer error not reported: %v", err)
+	}
+
+	dg.Add("A", "C")
+
+	err = dg.ResolveToWriter(&lineWriter{limit: 10}, strings.ToLower)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved graph with circular dependency to writer: %v", err)
+	}
+}
+
+// TestWriteWaves tests that the waves are separated by barrier lines.
+func TestWriteWaves(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("fetch")
+	dg.Add("lint")
+	dg.Add("build", "fetch")
+	dg.Add("test", "build", "lint")
+
+	buf := &strings.Builder{}
+
+	err := dg.WriteWaves(buf, "wait", func(node string) string {
+		return "make " + node + " &"
+	})
+	if err != nil {
+		t.Fatalf("writing waves: %v", err)
+	}
+
+	expected := "make fetch &\nmake lint &\nwait\nmake build &\nwait\nmake test &\n"
+	if buf.String() != expected {
+		t.Fatalf("waves written incorrectly:\n%s", buf.String())
+	}
+
+	err = dg.WriteWaves(&lineWriter{}, "wait", strings.ToUpper)
+	if err == nil {
+		t.Fatalf("writer error not reported when writing waves")
+	}
+
+	dg.Add("fetch", "test")
+
+	err = dg.WriteWaves(buf, "wait", strings.ToUpper)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("wrote waves of a graph with circular dependency: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package depgraph
+
+// KeyedGraph is a dependency graph over nodes of an arbitrary type, which need not be comparable,
+// such as interface values: every node is identified by the string returned by its id function,
+// while the original node values are stored and returned by the resolution.
+// Two nodes with the same id are treated as the same node.
+// All methods of DependencyGraph are available on it as well, operating on the ids.
+type KeyedGraph[T any] struct {
+	*DependencyGraph[string]
+
+	id    func(T) string
+	nodes map[string]T
+
+	// forget drops the ids memoized by NewCachedDependencyGraphBy, if any.
+	forget func()
+}
+
+// NewDependencyGraphBy creates a new stable dependency graph over nodes of type T,
+// which are identified by the given id function.
+// Its behavior may be tuned by passing one or more options, which operate on the ids.
+func NewDependencyGraphBy[T any](id func(T) string, opts ...Option[string]) *KeyedGraph[T] {
+	return &KeyedGraph[T]{
+		DependencyGraph: NewDependencyGraph(opts...),
+		id:              id,
+		nodes:           map[string]T{},
+	}
+}
+
+// NewCachedDependencyGraphBy works like NewDependencyGraphBy, but memoizes the id of every node value,
+// so that id is only called once per distinct value, however many times the value gets added,
+// either as a node or as a dependency; this pays off when computing an id is expensive,
+// such as when it hashes a large struct.
+// The node values must be comparable, and their ids must never change, which the memoization
+// would not notice; a pointer node keeps its id even if the value it points to changes.
+// The memoized ids are retained until the graph gets cleared.
+func NewCachedDependencyGraphBy[T comparable](id func(T) string, opts ...Option[string]) *KeyedGraph[T] {
+	ids := map[T]string{}
+
+	kg := NewDependencyGraphBy(func(node T) string {
+		key, ok := ids[node]
+		if !ok {
+			key = id(node)
+			ids[node] = key
+		}
+
+		return key
+	}, opts...)
+
+	kg.forget = func() {
+		clear(ids)
+	}
+
+	return kg
+}
+
+// AddNode adds a node along with its dependencies, just like Add does, keyed by their ids.
+// The node's value replaces the one stored for its id, if any;
+// the values of the dependencies are only stored if their ids have not been seen before.
+// The values are stored under the canonical ids, so an id may also be an alias.
+func (kg *KeyedGraph[T]) AddNode(node T, deps ...T) {
+	name := kg.id(node)
+	ids := make([]string, len(deps))
+
+	for i, dep := range deps {
+		ids[i] = kg.id(dep)
+
+		if key := kg.resolveAlias(ids[i]); !kg.hasNode(key) {
+			kg.nodes[key] = dep
+		}
+	}
+
+	kg.Add(name, ids...)
+	kg.nodes[kg.resolveAlias(name)] = node
+}
+
+// Node returns the node stored for an id, which may also be an alias, and whether it has been seen.
+func (kg *KeyedGraph[T]) Node(id string) (T, bool) {
+	node, ok := kg.nodes[kg.resolveAlias(id)]
+	return node, ok
+}
+
+// hasNode reports whether a node has been stored for a canonical id.
+func (kg *KeyedGraph[T]) hasNode(key string) bool {
+	_, ok := kg.nodes[key]
+	return ok
+}
+
+// Alias works like DependencyGraph.Alias, and additionally moves the node stored for alias, if any,
+// to canonical, unless a node has already been stored for canonical.
+func (kg *KeyedGraph[T]) Alias(canonical, alias string) error {
+	err := kg.DependencyGraph.Alias(canonical, alias)
+	if err != nil {
+		return err
+	}
+
+	if node, ok := kg.nodes[alias]; ok {
+		delete(kg.nodes, alias)
+
+		canonical = kg.resolveAlias(canonical)
+		if !kg.hasNode(canonical) {
+			kg.nodes[canonical] = node
+		}
+	}
+
+	return nil
+}
+
+// Clear removes all nodes from the graph, while retaining its allocated capacity.
+func (kg *KeyedGraph[T]) Clear() {
+	kg.DependencyGraph.Clear()
+	clear(kg.nodes)
+
+	if kg.forget != nil {
+		kg.forget()
+	}
+}
+
+// ResolveNodes returns the graph's nodes in dependency order.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (kg *KeyedGraph[T]) ResolveNodes() ([]T, error) {
+	res := make([]T, 0, len(kg.edges))
+
+	for id, err := range kg.ResolveIter() {
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, kg.nodes[id])
+	}
+
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// keyedNode is a test node type, whose values may only be compared by their id.
+type keyedNode interface {
+	ID() string
+}
+
+// keyedService is a keyedNode, which is not comparable.
+type keyedService struct {
+	name  string
+	ports []int
+}
+
+func (s keyedService) ID() string { return s.name }
+
+// TestKeyedGraph tests that nodes are identified by their ids, while the original values are returned.
+func TestKeyedGraph(t *testing.T) {
+	kg := NewDependencyGraphBy(keyedNode.ID)
+
+	db := keyedService{name: "db", ports: []int{5432}}
+	api := keyedService{name: "api", ports: []int{80, 443}}
+
+	kg.AddNode(api, keyedService{name: "db"})
+	kg.AddNode(db)
+	kg.AddNode(keyedService{name: "web"}, api, db)
+
+	res, err := kg.ResolveNodes()
+	if err != nil {
+		t.Fatalf("resolving keyed graph: %v", err)
+	}
+
+	ids := make([]string, len(res))
+	for i, node := range res {
+		ids[i] = node.ID()
+	}
+
+	if !slices.Equal(ids, []string{"db", "api", "web"}) {
+		t.Fatalf("keyed graph resolved incorrectly: %v", ids)
+	}
+
+	if node, _ := res[0].(keyedService); !slices.Equal(node.ports, db.ports) {
+		t.Fatalf("node value replaced by a dependency: %v", node)
+	}
+
+	if deps := kg.Dependencies("web"); !slices.Equal(deps, []string{"api", "db"}) {
+		t.Fatalf("keyed graph dependencies listed incorrectly: %v", deps)
+	}
+
+	kg.AddNode(db, keyedService{name: "web"})
+
+	_, err = kg.ResolveNodes()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved keyed graph with circular dependency: %v", err)
+	}
+}
+
+// TestCachedKeyedGraph tests that the id of every distinct node value is only computed once,
+// until the graph gets cleared.
+func TestCachedKeyedGraph(t *testing.T) {
+	type blob struct {
+		name string
+		data [64]byte
+	}
+
+	calls := 0
+	kg := NewCachedDependencyGraphBy(func(b blob) string {
+		calls++
+		return b.name
+	})
+
+	lib, util := blob{name: "lib"}, blob{name: "util"}
+	kg.AddNode(util)
+	kg.AddNode(lib, util)
+	kg.AddNode(blob{name: "app"}, lib, util)
+	kg.AddNode(blob{name: "test"}, lib)
+
+	res, err := kg.ResolveNodes()
+	if err != nil {
+		t.Fatalf("resolving cached keyed graph: %v", err)
+	}
+
+	if len(res) != 4 || res[0] != util || res[1] != lib {
+		t.Fatalf("cached keyed graph resolved incorrectly: %v", res)
+	}
+
+	if calls != 4 {
+		t.Fatalf("ids computed %d times instead of once per node", calls)
+	}
+
+	kg.Clear()
+	kg.AddNode(lib)
+
+	if calls != 5 {
+		t.Fatalf("memoized ids retained after clearing the graph")
+	}
+}
+
+// TestKeyedGraphAlias tests that the node values are stored under the canonical ids,
+// and that they may be looked up by any of their aliases.
+func TestKeyedGraphAlias(t *testing.T) {
+	kg := NewDependencyGraphBy(keyedNode.ID)
+
+	err := kg.Alias("w", "v")
+	if err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+
+	kg.AddNode(keyedService{name: "v", ports: []int{80}})
+
+	res, err := kg.ResolveNodes()
+	if err != nil {
+		t.Fatalf("resolving keyed graph with alias: %v", err)
+	}
+
+	if len(res) != 1 || res[0].ID() != "v" {
+		t.Fatalf("node added by alias lost: %v", res)
+	}
+
+	kg.AddNode(keyedService{name: "x"}, keyedService{name: "y"})
+
+	err = kg.Alias("z", "y")
+	if err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+
+	if node, ok := kg.Node("z"); !ok || node.ID() != "y" {
+		t.Fatalf("node of alias not moved to canonical id: %v, %v", node, ok)
+	}
+
+	if node, ok := kg.Node("v"); !ok || node.ID() != "v" {
+		t.Fatalf("node not found by alias: %v, %v", node, ok)
+	}
+
+	if _, ok := kg.Node("u"); ok {
+		t.Fatalf("node found for unknown id")
+	}
+
+	err = kg.Alias("x", "x")
+	if !errors.Is(err, ErrAliasCycle) {
+		t.Fatalf("aliased node to itself: %v", err)
+	}
+}
@@ -0,0 +1,539 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// ResolveBatchIter returns an iterator that lazily yields the graph's elements in waves:
+// every wave contains the elements, all of whose dependencies belong to the preceding waves,
+// so that the elements of a single wave may be processed in parallel.
+// Within a wave, the elements follow the edge list order.
+// Elements declared exclusive with Exclusive never share a wave: the ones conflicting with a preceding element
+// of the wave are deferred to the next one.
+// If a circular dependency is detected, or if the graph is invalid,
+// the iterator yields a pair of (nil, *ResolveError) and stops.
+func (dg *DependencyGraph[T]) ResolveBatchIter() iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		err := dg.Validate()
+		if err != nil {
+			yield(nil, &ResolveError{
+				Total: len(dg.edges),
+				Err:   fmt.Errorf("validating dependency graph: %w", err),
+			})
+
+			return
+		}
+
+		edges := dg.edges
+		refcounts, dependents := dg.adjacency()
+
+		free := []int{}
+		for i := range edges {
+			if refcounts[i] == 0 {
+				free = append(free, i)
+			}
+		}
+
+		resolved := 0
+		used := make([]bool, dg.exclusiveSets)
+
+		for len(free) > 0 {
+			// Free elements, which conflict with an exclusive element of this wave, are deferred to the next one.
+			wave := free
+			next := []int{}
+
+			if dg.exclusiveSets > 0 {
+				clear(used)
+
+				wave = []int{}
+				for _, idx := range free {
+					if dg.claimExclusive(edges[idx].name, used) {
+						wave = append(wave, idx)
+					} else {
+						next = append(next, idx)
+					}
+				}
+			}
+
+			batch := make([]T, len(wave))
+			for i, idx := range wave {
+				batch[i] = edges[idx].name
+			}
+
+			if !yield(batch, nil) {
+				return
+			}
+
+			resolved += len(wave)
+
+			// Collect the elements, which have been freed by this wave.
+			for _, idx := range wave {
+				for _, d := range dependents[idx] {
+					refcounts[d]--
+
+					if refcounts[d] == 0 {
+						next = append(next, d)
+					}
+				}
+			}
+
+			slices.Sort(next)
+			free = next
+		}
+
+		if resolved != len(edges) {
+			yield(nil, &ResolveError{
+				Resolved: resolved,
+				Total:    len(edges),
+				Err:      ErrCircularDependency,
+			})
+		}
+	}
+}
+
+// ResolveLevels returns the graph's elements split into waves, as yielded by ResolveBatchIter.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveLevels() ([][]T, error) {
+	res := [][]T{}
+
+	for batch, err := range dg.ResolveBatchIter() {
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, batch)
+	}
+
+	return res, nil
+}
+
+// ResolveLevelsMax works like ResolveLevels, but splits every wave larger than maxBatch
+// into consecutive sub-waves of at most maxBatch elements, preserving the order of the elements,
+// so that no batch exceeds the limit even if more elements are free.
+// A limit less than one is treated as one.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveLevelsMax(maxBatch int) ([][]T, error) {
+	maxBatch = max(maxBatch, 1)
+	res := [][]T{}
+
+	for batch, err := range dg.ResolveBatchIter() {
+		if err != nil {
+			return nil, err
+		}
+
+		for len(batch) > maxBatch {
+			res = append(res, batch[:maxBatch:maxBatch])
+			batch = batch[maxBatch:]
+		}
+
+		res = append(res, batch)
+	}
+
+	return res, nil
+}
+
+// MaxWidth returns the size of the largest wave, as yielded by ResolveBatchIter,
+// which is the peak number of elements that may be processed in parallel:
+// adding workers beyond it does not make processing the graph wave by wave any faster.
+// An empty graph has a width of zero.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) MaxWidth() (int, error) {
+	width := 0
+
+	for batch, err := range dg.ResolveBatchIter() {
+		if err != nil {
+			return 0, err
+		}
+
+		width = max(width, len(batch))
+	}
+
+	return width, nil
+}
+
+// OrderingSensitivity measures how much the resolution order relies on the insertion order:
+// it returns the number of tie-break decisions made by Resolve, that is, the number of times
+// an element has been picked while several elements were free.
+// Each of these decisions is up to the insertion order, so a small change of the input
+// may change the resulting order; a high number suggests adding explicit ordering constraints.
+// An empty graph, as well as a single chain of elements, makes no decisions at all.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) OrderingSensitivity() (int, error) {
+	order, err := dg.Resolve()
+	if err != nil {
+		return 0, err
+	}
+
+	refcounts, dependents := dg.adjacency()
+
+	positions := make(map[T]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		positions[edge.name] = i
+	}
+
+	free := 0
+	for _, count := range refcounts {
+		if count == 0 {
+			free++
+		}
+	}
+
+	// Replay the resolution, keeping track of the number of free elements.
+	decisions := 0
+
+	for _, name := range order {
+		if free > 1 {
+			decisions++
+		}
+
+		free--
+
+		for _, d := range dependents[positions[name]] {
+			refcounts[d]--
+
+			if refcounts[d] == 0 {
+				free++
+			}
+		}
+	}
+
+	return decisions, nil
+}
+
+// MaximumAntichain returns the largest set of mutually independent elements, that is, of the elements,
+// none of which depends on another one, either directly or transitively.
+// By the Dilworth's theorem, its size is the true peak parallelism of the graph, which may exceed
+// the width of any single wave, as returned by MaxWidth, since independent elements may belong to different waves.
+// The elements depending on each other through a cycle are never independent, so at most one of them,
+// the earliest one in the edge list, may be a part of the set; unknown dependencies are ignored.
+// The set is computed from a maximum matching over the transitive closure, in a cubic time
+// in the worst case; the elements are returned in the edge list order.
+func (dg *DependencyGraph[T]) MaximumAntichain() []T {
+	_, members, closures := dg.componentClosures()
+	comps := len(members)
+
+	// Every component is split into a left and a right copy, with the left copy of a component
+	// linked to the right copies of all the components it transitively depends on.
+	matchL := make([]int, comps)
+	matchR := make([]int, comps)
+
+	for c := range comps {
+		matchL[c], matchR[c] = -1, -1
+	}
+
+	// Match greedily first, so that the augmenting paths only have to resolve the remaining conflicts.
+	for u, closure := range closures {
+		for v := nextBit(closure, 0); v >= 0; v = nextBit(closure, v+1) {
+			if matchR[v] < 0 {
+				matchL[u], matchR[v] = v, u
+				break
+			}
+		}
+	}
+
+	type frame struct {
+		node, next int
+	}
+
+	seen := make([]int, comps)
+	stack := []frame{}
+
+	// The augmenting paths are searched for with an explicit stack, since they may be arbitrarily long.
+	for root := range comps {
+		if matchL[root] >= 0 {
+			continue
+		}
+
+		stamp := root + 1
+		stack = append(stack[:0], frame{node: root})
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			v := nextBit(closures[top.node], top.next)
+			if v < 0 {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			top.next = v + 1
+
+			if seen[v] == stamp {
+				continue
+			}
+
+			seen[v] = stamp
+
+			if matchR[v] >= 0 {
+				stack = append(stack, frame{node: matchR[v]})
+				continue
+			}
+
+			// Flip the path: every left copy on the stack gets matched to the right copy it has tried last.
+			for _, f := range stack {
+				matchL[f.node], matchR[f.next-1] = f.next-1, f.node
+			}
+
+			break
+		}
+	}
+
+	// By the König's theorem, the components reachable from the unmatched left copies through
+	// alternating paths, whose right copy is not reachable as well, form the maximum antichain.
+	visitedL := make([]bool, comps)
+	visitedR := make([]bool, comps)
+	queue := []int{}
+
+	for u := range comps {
+		if matchL[u] < 0 {
+			visitedL[u] = true
+			queue = append(queue, u)
+		}
+	}
+
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+
+		for v := nextBit(closures[u], 0); v >= 0; v = nextBit(closures[u], v+1) {
+			if visitedR[v] || matchL[u] == v {
+				continue
+			}
+
+			visitedR[v] = true
+
+			if w := matchR[v]; w >= 0 && !visitedL[w] {
+				visitedL[w] = true
+				queue = append(queue, w)
+			}
+		}
+	}
+
+	picked := []int{}
+	for c := range comps {
+		if visitedL[c] && !visitedR[c] {
+			picked = append(picked, members[c][0])
+		}
+	}
+
+	slices.Sort(picked)
+
+	res := make([]T, len(picked))
+	for i, e := range picked {
+		res[i] = dg.edges[e].name
+	}
+
+	return res
+}
+
+// ParallelismImpact reports how adding a dependency of name on dep would affect the parallelism,
+// by returning the graph's width, as returned by MaxWidth, both before and after adding it hypothetically.
+// The graph itself is not modified.
+// An error is returned if either of the graphs cannot be resolved, such as if the dependency would
+// introduce a circular dependency.
+func (dg *DependencyGraph[T]) ParallelismImpact(name, dep T) (int, int, error) {
+	before, err := dg.MaxWidth()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	hypothetical := dg.derive(func(_ T) bool {
+		return true
+	}, func(_, _ T) bool {
+		return true
+	})
+
+	hypothetical.Add(name, dep)
+
+	after, err := hypothetical.MaxWidth()
+	if err != nil {
+		return 0, 0, fmt.Errorf("adding dependency \"%v\" of \"%v\": %w", dep, name, err)
+	}
+
+	return before, after, nil
+}
+
+// ResolvedNode is an element of the resolution order, annotated with additional details.
+type ResolvedNode[T comparable] struct {
+	// Node is the resolved element.
+	Node T
+
+	// Level is the index of the wave, which the element belongs to, as yielded by ResolveBatchIter.
+	// Elements of the same level are independent of each other.
+	Level int
+
+	// Deps holds the direct dependencies of the element, in the order in which they have been added.
+	Deps []T
+
+	// UnlockedBy is the dependency, which has been resolved last among the element's dependencies,
+	// thus making the element free; it is only meaningful if HasUnlocker is true.
+	// Following it back allows reconstructing the chain of scheduling decisions.
+	UnlockedBy T
+
+	// HasUnlocker is false for elements, which have been free from the very start.
+	HasUnlocker bool
+}
+
+// ResolveIterWithDeps returns an iterator that yields the graph's elements in the same order
+// as ResolveIter does, annotating each of them with its level, a copy of its direct dependencies
+// and the dependency which has unlocked it.
+// Errors are handled in the same way as in ResolveIter.
+func (dg *DependencyGraph[T]) ResolveIterWithDeps() iter.Seq2[ResolvedNode[T], error] {
+	return func(yield func(ResolvedNode[T], error) bool) {
+		type position struct {
+			index int
+			level int
+		}
+
+		resolved := make(map[T]position, len(dg.edges))
+
+		// Exclusive elements may be deferred to later waves, which cannot be told from the dependencies alone.
+		var levels map[T]int
+		if dg.exclusiveSets > 0 {
+			levels = make(map[T]int, len(dg.edges))
+
+			level := 0
+			for batch := range dg.ResolveBatchIter() {
+				for _, el := range batch {
+					levels[el] = level
+				}
+
+				level++
+			}
+		}
+
+		for el, err := range dg.ResolveIter() {
+			if err != nil {
+				yield(ResolvedNode[T]{}, err)
+				return
+			}
+
+			edge := dg.edgeMap[el]
+
+			node := ResolvedNode[T]{
+				Node: el,
+				Deps: slices.Clone(edge.depOrder),
+			}
+
+			// All known dependencies have already been resolved,
+			// so the element's level is right after the highest level among them,
+			// and the one resolved last is the one that has unlocked the element.
+			last := -1
+			for _, dep := range edge.depOrder {
+				pos, ok := resolved[dep]
+				if !ok {
+					continue
+				}
+
+				node.Level = max(node.Level, pos.level+1)
+
+				if pos.index > last {
+					last = pos.index
+					node.UnlockedBy = dep
+					node.HasUnlocker = true
+				}
+			}
+
+			if levels != nil {
+				node.Level = levels[el]
+			}
+
+			resolved[el] = position{
+				index: len(resolved),
+				level: node.Level,
+			}
+
+			if !yield(node, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ResolveAnnotated returns the graph's elements in the same order as Resolve does,
+// annotated as by ResolveIterWithDeps.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveAnnotated() ([]ResolvedNode[T], error) {
+	res := make([]ResolvedNode[T], 0, len(dg.edges))
+
+	for node, err := range dg.ResolveIterWithDeps() {
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, node)
+	}
+
+	return res, nil
+}
+
+// Plan is a resolution of a graph, combining the sequential order with the parallel schedule.
+type Plan[T comparable] struct {
+	// Order holds the graph's elements in dependency order, as returned by Resolve.
+	Order []T
+
+	// Levels holds the graph's elements split into waves, as returned by ResolveLevels.
+	Levels [][]T
+
+	// Ranks maps every element to its position in Order.
+	Ranks map[T]int
+
+	// MaxWidth is the size of the largest wave, as returned by MaxWidth.
+	MaxWidth int
+
+	// Acyclic is false if the graph contains a circular dependency,
+	// in which case the rest of the plan only covers the elements that could be resolved.
+	Acyclic bool
+}
+
+// Plan resolves the graph into both a sequential order and a parallel schedule at once,
+// which is cheaper than calling Resolve, ResolveLevels, Ranks and MaxWidth separately.
+// A circular dependency is reported by the plan itself, while other failures make it return a *ResolveError.
+func (dg *DependencyGraph[T]) Plan() (Plan[T], error) {
+	plan := Plan[T]{
+		Order:   make([]T, 0, len(dg.edges)),
+		Levels:  [][]T{},
+		Ranks:   make(map[T]int, len(dg.edges)),
+		Acyclic: true,
+	}
+
+	for node, err := range dg.ResolveIterWithDeps() {
+		if errors.Is(err, ErrCircularDependency) {
+			plan.Acyclic = false
+			break
+		}
+
+		if err != nil {
+			return Plan[T]{}, err
+		}
+
+		plan.Ranks[node.Node] = len(plan.Order)
+		plan.Order = append(plan.Order, node.Node)
+
+		// Exclusive elements may be deferred, so a wave may get its first element after the later waves.
+		for len(plan.Levels) <= node.Level {
+			plan.Levels = append(plan.Levels, []T{})
+		}
+
+		plan.Levels[node.Level] = append(plan.Levels[node.Level], node.Node)
+	}
+
+	// Within a wave, the elements follow the edge list order, rather than the resolution order.
+	positions := make(map[T]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		positions[edge.name] = i
+	}
+
+	for _, level := range plan.Levels {
+		slices.SortFunc(level, func(a, b T) int {
+			return positions[a] - positions[b]
+		})
+
+		plan.MaxWidth = max(plan.MaxWidth, len(level))
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,479 @@
+package depgraph
+
+import (
+	"errors"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// TestResolveLevels tests that the graph gets split into correct waves.
+func TestResolveLevels(t *testing.T) {
+	tbl := []struct {
+		in       [][]string // [0]: element; [1:]: element's dependencies
+		out      [][]string
+		circular bool
+	}{
+		{
+			in:  [][]string{},
+			out: [][]string{},
+		},
+		{
+			in:  [][]string{{"A"}, {"B", "A"}, {"C"}, {"D", "B", "A"}, {"E"}},
+			out: [][]string{{"A", "C", "E"}, {"B"}, {"D"}},
+		},
+		{
+			in:  [][]string{{"D", "B", "C"}, {"C", "A"}, {"B", "A"}, {"A"}},
+			out: [][]string{{"A"}, {"C", "B"}, {"D"}},
+		},
+		{
+			in:       [][]string{{"A"}, {"B", "A", "C"}, {"C", "B"}},
+			circular: true,
+		},
+	}
+
+	for _, test := range tbl {
+		dg := NewDependencyGraph[string]()
+
+		for _, in := range test.in {
+			dg.Add(in[0], in[1:]...)
+		}
+
+		res, err := dg.ResolveLevels()
+		if err != nil {
+			if test.circular && errors.Is(err, ErrCircularDependency) {
+				continue
+			}
+
+			t.Fatalf("resolving graph levels: input = %v: %v", test.in, err)
+		}
+
+		if test.circular {
+			t.Fatalf("resolved levels of graph with circular dependency: input = %v", test.in)
+		}
+
+		if !slices.EqualFunc(res, test.out, slices.Equal) {
+			t.Fatalf("graph levels resolved incorrectly: input = %v; output = %v; expected = %v", test.in, res, test.out)
+		}
+	}
+}
+
+// TestBatchIter tests that the batch iterator allows us to exit early,
+// and that it reports how far it got before detecting a circular dependency.
+func TestBatchIter(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "B")
+	dg.Add("D", "E")
+	dg.Add("E", "D")
+
+	res := [][]string{}
+	for batch, err := range dg.ResolveBatchIter() {
+		if err != nil {
+			t.Fatalf("resolving graph in batches: %v", err)
+		}
+
+		res = append(res, batch)
+		if len(res) == 2 {
+			break
+		}
+	}
+
+	if !slices.EqualFunc(res, [][]string{{"A"}, {"B"}}, slices.Equal) {
+		t.Fatalf("graph batches resolved incorrectly: %v", res)
+	}
+
+	var resErr *ResolveError
+
+	_, err := dg.ResolveLevels()
+	if !errors.As(err, &resErr) || !errors.Is(err, ErrCircularDependency) || resErr.Resolved != 3 {
+		t.Fatalf("unexpected error when resolving levels of a circular graph: %v", err)
+	}
+}
+
+// TestMaxWidth tests that the width is the size of the largest wave.
+func TestMaxWidth(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+
+	width, err := dg.MaxWidth()
+	if err != nil || width != 0 {
+		t.Fatalf("width of an empty graph computed incorrectly: %v, %v", width, err)
+	}
+
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "A")
+	dg.Add("D", "A")
+	dg.Add("E")
+	dg.Add("F", "B", "C", "D")
+
+	width, err = dg.MaxWidth()
+	if err != nil {
+		t.Fatalf("computing graph width: %v", err)
+	}
+
+	if width != 3 {
+		t.Fatalf("graph width computed incorrectly: %d", width)
+	}
+
+	dg.Add("A", "F")
+
+	_, err = dg.MaxWidth()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("computed width of a graph with circular dependency: %v", err)
+	}
+}
+
+// TestResolveLevelsMax tests that waves larger than the limit get split, preserving the order.
+func TestResolveLevelsMax(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "A")
+	dg.Add("D", "A")
+	dg.Add("E")
+	dg.Add("F", "B", "C", "D")
+
+	tbl := []struct {
+		limit int
+		out   [][]string
+	}{
+		{limit: 3, out: [][]string{{"A", "E"}, {"B", "C", "D"}, {"F"}}},
+		{limit: 2, out: [][]string{{"A", "E"}, {"B", "C"}, {"D"}, {"F"}}},
+		{limit: 0, out: [][]string{{"A"}, {"E"}, {"B"}, {"C"}, {"D"}, {"F"}}},
+	}
+
+	for _, test := range tbl {
+		res, err := dg.ResolveLevelsMax(test.limit)
+		if err != nil {
+			t.Fatalf("resolving limited graph levels: limit = %d: %v", test.limit, err)
+		}
+
+		if !slices.EqualFunc(res, test.out, slices.Equal) {
+			t.Fatalf("limited graph levels resolved incorrectly: limit = %d; output = %v", test.limit, res)
+		}
+	}
+
+	dg.Add("A", "F")
+
+	_, err := dg.ResolveLevelsMax(2)
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved limited levels of a graph with circular dependency: %v", err)
+	}
+}
+
+// TestOrderingSensitivity tests that the tie-break decisions get counted.
+func TestOrderingSensitivity(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("C", "B")
+	dg.Add("B", "A")
+	dg.Add("A")
+
+	decisions, err := dg.OrderingSensitivity()
+	if err != nil || decisions != 0 {
+		t.Fatalf("ordering sensitivity of a chain computed incorrectly: %v, %v", decisions, err)
+	}
+
+	dg = NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "A")
+	dg.Add("D", "A")
+	dg.Add("E")
+	dg.Add("F", "B", "C", "D")
+
+	decisions, err = dg.OrderingSensitivity()
+	if err != nil {
+		t.Fatalf("computing ordering sensitivity: %v", err)
+	}
+
+	if decisions != 4 {
+		t.Fatalf("ordering sensitivity computed incorrectly: %d", decisions)
+	}
+
+	dg.Add("A", "F")
+
+	_, err = dg.OrderingSensitivity()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("computed ordering sensitivity of a graph with circular dependency: %v", err)
+	}
+}
+
+// TestMaximumAntichain tests that the largest set of independent elements is found,
+// comparing its size against all subsets of small random graphs.
+func TestMaximumAntichain(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "B")
+	dg.Add("D")
+	dg.Add("E", "D")
+	dg.Add("F")
+	dg.Add("G", "A")
+
+	width, err := dg.MaxWidth()
+	if err != nil {
+		t.Fatalf("computing graph width: %v", err)
+	}
+
+	if res := dg.MaximumAntichain(); len(res) != 4 || width != 3 {
+		t.Fatalf("maximum antichain found incorrectly: %v; width = %d", res, width)
+	}
+
+	// At most one element of a cycle may be picked.
+	dg.Add("H", "I")
+	dg.Add("I", "H")
+
+	if res := dg.MaximumAntichain(); len(res) != 5 || !slices.Contains(res, "H") {
+		t.Fatalf("maximum antichain of a graph with cycle found incorrectly: %v", res)
+	}
+
+	rng := rand.New(rand.NewPCG(3, 3))
+
+	for range 50 {
+		n := 1 + rng.IntN(10)
+		dg := NewDependencyGraph[int]()
+
+		for i := range n {
+			dg.Add(i)
+
+			for range rng.IntN(3) {
+				dg.Add(i, rng.IntN(n))
+			}
+		}
+
+		independent := func(set []int) bool {
+			for _, a := range set {
+				for _, b := range set {
+					if a != b && dg.reaches(a, b) {
+						return false
+					}
+				}
+			}
+
+			return true
+		}
+
+		best := 0
+		for mask := range 1 << n {
+			set := []int{}
+			for i := range n {
+				if mask&(1<<i) != 0 {
+					set = append(set, i)
+				}
+			}
+
+			if len(set) > best && independent(set) {
+				best = len(set)
+			}
+		}
+
+		res := dg.MaximumAntichain()
+		if len(res) != best || !independent(res) {
+			t.Fatalf("maximum antichain of random graph found incorrectly: output = %v; expected size = %d", res, best)
+		}
+	}
+}
+
+// TestParallelismImpact tests that the width is computed with and without a hypothetical dependency.
+func TestParallelismImpact(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C")
+	dg.Add("D", "A")
+
+	before, after, err := dg.ParallelismImpact("B", "A")
+	if err != nil {
+		t.Fatalf("computing parallelism impact: %v", err)
+	}
+
+	if before != 3 || after != 2 {
+		t.Fatalf("parallelism impact computed incorrectly: %d -> %d", before, after)
+	}
+
+	if deps := dg.Dependencies("B"); len(deps) != 0 {
+		t.Fatalf("graph modified by computing parallelism impact: %v", deps)
+	}
+
+	_, _, err = dg.ParallelismImpact("A", "D")
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("parallelism impact of a circular dependency computed: %v", err)
+	}
+
+	dg.Add("A", "D")
+
+	_, _, err = dg.ParallelismImpact("B", "C")
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("parallelism impact on a graph with circular dependency computed: %v", err)
+	}
+}
+
+// TestResolveAnnotated tests that the resolution order gets annotated with levels.
+func TestResolveAnnotated(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "B", "A")
+	dg.Add("E", "C")
+
+	res, err := dg.ResolveAnnotated()
+	if err != nil {
+		t.Fatalf("resolving annotated graph: %v", err)
+	}
+
+	expected := []ResolvedNode[string]{
+		{Node: "A", Level: 0, Deps: []string{}},
+		{Node: "C", Level: 0, Deps: []string{}},
+		{Node: "B", Level: 1, Deps: []string{"A"}},
+		{Node: "E", Level: 1, Deps: []string{"C"}},
+		{Node: "D", Level: 2, Deps: []string{"B", "A"}},
+	}
+
+	if !slices.EqualFunc(res, expected, equalResolvedNodes) {
+		t.Fatalf("annotated graph resolved incorrectly: %v", res)
+	}
+
+	dg.Add("A", "D")
+
+	_, err = dg.ResolveAnnotated()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved annotated graph with circular dependency: %v", err)
+	}
+}
+
+// TestResolveAnnotatedUnlocker tests that every element is annotated with the dependency,
+// which has been resolved last among its dependencies, regardless of the order of the dependencies.
+func TestResolveAnnotatedUnlocker(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "A", "B")
+	dg.Add("E", "C")
+
+	res, err := dg.ResolveAnnotated()
+	if err != nil {
+		t.Fatalf("resolving annotated graph: %v", err)
+	}
+
+	expected := map[string]string{"B": "A", "E": "C", "D": "B"}
+
+	for _, node := range res {
+		unlocker, ok := expected[node.Node]
+		if node.HasUnlocker != ok || node.UnlockedBy != unlocker {
+			t.Fatalf("unlocking dependency of \"%s\" reported incorrectly: %v, %v", node.Node, node.UnlockedBy, node.HasUnlocker)
+		}
+	}
+}
+
+// TestResolveIterWithDeps tests that the annotated iterator allows us to exit early,
+// and that the yielded dependencies are copies.
+func TestResolveIterWithDeps(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "B")
+
+	res := []ResolvedNode[string]{}
+	for node, err := range dg.ResolveIterWithDeps() {
+		if err != nil {
+			t.Fatalf("resolving annotated graph iteratively: %v", err)
+		}
+
+		res = append(res, node)
+		if node.Node == "B" {
+			node.Deps[0] = "X"
+			break
+		}
+	}
+
+	if len(res) != 2 || res[1].Level != 1 {
+		t.Fatalf("annotated graph resolved iteratively incorrectly: %v", res)
+	}
+
+	if deps := dg.Dependencies("B"); !slices.Equal(deps, []string{"A"}) {
+		t.Fatalf("graph modified through yielded dependencies: %v", deps)
+	}
+}
+
+// TestResolveAnnotatedUnknown tests that the unknown dependencies, which have survived the validation,
+// affect neither the level of an element, nor its unlocking dependency.
+func TestResolveAnnotatedUnknown(t *testing.T) {
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		return nil
+	}))
+	dg.Add("A", "X")
+	dg.Add("B", "A", "Y")
+
+	res, err := dg.ResolveAnnotated()
+	if err != nil {
+		t.Fatalf("resolving annotated graph with unknown dependencies: %v", err)
+	}
+
+	expected := []ResolvedNode[string]{
+		{Node: "A", Level: 0, Deps: []string{"X"}},
+		{Node: "B", Level: 1, Deps: []string{"A", "Y"}},
+	}
+
+	if !slices.EqualFunc(res, expected, equalResolvedNodes) || res[0].HasUnlocker || res[1].UnlockedBy != "A" {
+		t.Fatalf("annotated graph with unknown dependencies resolved incorrectly: %v", res)
+	}
+}
+
+// equalResolvedNodes compares resolved nodes, treating nil and empty dependency lists as equal.
+func equalResolvedNodes(a, b ResolvedNode[string]) bool {
+	return a.Node == b.Node && a.Level == b.Level && slices.Equal(a.Deps, b.Deps)
+}
+
+// TestPlan tests that the plan agrees with the separate resolution methods.
+func TestPlan(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("D", "B", "C")
+	dg.Add("B", "A")
+	dg.Add("C", "A")
+	dg.Add("A")
+	dg.Add("E")
+	dg.Exclusive("A", "E")
+
+	plan, err := dg.Plan()
+	if err != nil {
+		t.Fatalf("planning graph: %v", err)
+	}
+
+	order := dg.MustResolve()
+
+	levels, err := dg.ResolveLevels()
+	if err != nil {
+		t.Fatalf("resolving graph levels: %v", err)
+	}
+
+	ranks, err := dg.Ranks()
+	if err != nil {
+		t.Fatalf("computing graph ranks: %v", err)
+	}
+
+	width, err := dg.MaxWidth()
+	if err != nil {
+		t.Fatalf("computing graph width: %v", err)
+	}
+
+	if !plan.Acyclic || !slices.Equal(plan.Order, order) || !slices.EqualFunc(plan.Levels, levels, slices.Equal) ||
+		!maps.Equal(plan.Ranks, ranks) || plan.MaxWidth != width {
+		t.Fatalf("graph planned incorrectly: %+v", plan)
+	}
+
+	dg.Add("A", "D")
+
+	plan, err = dg.Plan()
+	if err != nil {
+		t.Fatalf("planning graph with circular dependency: %v", err)
+	}
+
+	if plan.Acyclic || !slices.Equal(plan.Order, []string{"E"}) {
+		t.Fatalf("graph with circular dependency planned incorrectly: %+v", plan)
+	}
+}
@@ -0,0 +1,45 @@
+package depgraph
+
+// Normalize cleans up the artifacts, which a series of structural edits may leave in the graph:
+// it removes duplicate dependencies, dependencies of nodes on themselves,
+// and dependencies on nodes that are not present in the graph, unless they are weak ones,
+// as added by AddWeak, which are expected to refer to the nodes that may be added later.
+// Unlike the unknown dependency handler, which only decides how unknown dependencies
+// are treated during the resolution, Normalize removes them for good.
+// It returns the number of removed dependencies.
+func (dg *DependencyGraph[T]) Normalize() int {
+	dg.mutate()
+
+	removed := 0
+
+	for _, edge := range dg.edges {
+		seen := make(map[T]struct{}, len(edge.depOrder))
+		kept := edge.depOrder[:0]
+
+		for _, dep := range edge.depOrder {
+			_, dup := seen[dep]
+			_, known := dg.edgeMap[dep]
+			_, weak := edge.weak[dep]
+
+			if dup || (!known && !weak) || dep == edge.name {
+				if !dup {
+					delete(edge.deps, dep)
+					delete(edge.costs, dep)
+					delete(edge.weak, dep)
+				}
+
+				removed++
+
+				continue
+			}
+
+			seen[dep] = struct{}{}
+			kept = append(kept, dep)
+		}
+
+		clear(edge.depOrder[len(kept):])
+		edge.depOrder = kept
+	}
+
+	return removed
+}
@@ -0,0 +1,62 @@
+package depgraph
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestNormalize tests that self-loops and dangling dependencies left by aliasing get removed.
+func TestNormalize(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "db", "postgres", "cache")
+	dg.Add("postgres", "db", "disk")
+	dg.Add("db")
+
+	err := dg.Alias("db", "postgres")
+	if err != nil {
+		t.Fatalf("aliasing node: %v", err)
+	}
+
+	if removed := dg.Normalize(); removed != 3 {
+		t.Fatalf("unexpected number of removed dependencies: %d", removed)
+	}
+
+	if deps := dg.Dependencies("app"); !slices.Equal(deps, []string{"db"}) {
+		t.Fatalf("dependencies normalized incorrectly: %v", deps)
+	}
+
+	if deps := dg.Dependencies("db"); len(deps) != 0 {
+		t.Fatalf("self-loop not removed: %v", deps)
+	}
+
+	if removed := dg.Normalize(); removed != 0 {
+		t.Fatalf("normalized graph changed again: %d", removed)
+	}
+}
+
+// TestNormalizeWeak tests that weak dependencies on nodes, which are not present yet, are kept,
+// while weak self-loops are removed along with their weak flag.
+func TestNormalizeWeak(t *testing.T) {
+	dg := NewDependencyGraph(WithAutoNormalize[string]())
+	dg.AddWeak("app", "docs")
+	dg.AddWeak("app", "app")
+	dg.Add("lib")
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"app", "lib"}) {
+		t.Fatalf("graph with weak dependencies normalized incorrectly: %v", res)
+	}
+
+	if deps := dg.Dependencies("app"); !slices.Equal(deps, []string{"docs"}) {
+		t.Fatalf("weak dependencies normalized incorrectly: %v", deps)
+	}
+
+	if dg.isWeak("app", "app") {
+		t.Fatalf("weak flag of a removed self-loop left behind")
+	}
+
+	dg.Add("docs")
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"lib", "docs", "app"}) {
+		t.Fatalf("weak dependency on a node added later ignored: %v", res)
+	}
+}
@@ -0,0 +1,122 @@
+package depgraph
+
+import "log/slog"
+
+// Option configures a dependency graph upon its creation.
+type Option[T comparable] func(dg *DependencyGraph[T])
+
+// WithUnknownHandler sets a handler that gets invoked during the graph validation
+// for each dependency, which is not known to the graph.
+// If the handler returns nil, the dependency is skipped and treated as satisfied;
+// otherwise, the resolution is aborted with the returned error.
+func WithUnknownHandler[T comparable](handler func(node, dep T) error) Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.unknownHandler = handler
+	}
+}
+
+// WithStrictExclusions makes ResolveExcluding fail with ErrExcludedDependency
+// if a node, which is not excluded, depends on an excluded one.
+// By default, such dependencies are simply dropped.
+func WithStrictExclusions[T comparable]() Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.strictExclusions = true
+	}
+}
+
+// WithLogger enables tracing of the resolution: every node, which gets promoted to the free list
+// or resolved, is logged at the debug level, along with the number of its satisfied dependencies
+// or its position in the resolution order.
+// Tracing is disabled by default.
+func WithLogger[T comparable](logger *slog.Logger) Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.logger = logger
+	}
+}
+
+// WithRejectDuplicateArgs makes Add panic with ErrDuplicateDependency when the same dependency
+// is listed more than once in a single call, which usually indicates a bug in the code building the graph.
+// Listing the same dependency in separate calls is still allowed.
+// By default, duplicate dependencies are silently deduplicated.
+func WithRejectDuplicateArgs[T comparable]() Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.rejectDuplicateArgs = true
+	}
+}
+
+// WithNodeProvider sets a provider, which gets asked to supply each unknown dependency
+// during the graph validation.
+// If the provider returns true, the missing node is added to the graph along with the returned
+// dependencies, which get validated in turn, allowing the graph to be expanded on demand.
+// Otherwise, the dependency is handled as any other unknown one.
+// The provider is not consulted for frozen graphs, since they cannot be expanded.
+func WithNodeProvider[T comparable](provider func(dep T) ([]T, bool)) Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.nodeProvider = provider
+	}
+}
+
+// WithRequireConnected makes the validation fail with ErrDisconnectedGraph if the graph
+// consists of more than one weakly connected component, that is, if some of its elements
+// are not linked to the others by any dependencies, regardless of their direction.
+// The error names a representative element of each component.
+func WithRequireConnected[T comparable]() Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.requireConnected = true
+	}
+}
+
+// WithInPlaceResolve makes the resolution reorder the graph's edge list in-place,
+// instead of working on a copy of it, which saves an allocation per resolution.
+// As a side effect, the elements added after a resolution may get resolved in a different order
+// than they would be otherwise, since the edge list order drives the tie-breaks.
+// Frozen graphs are always resolved on a copy.
+func WithInPlaceResolve[T comparable]() Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.inPlaceResolve = true
+	}
+}
+
+// WithReachabilityCache makes the graph cache its transitive closure, so that WouldCycle,
+// DependsOn and the other reachability queries take a constant time, rather than a traversal each.
+// The closure is built by the first query, then kept up to date as dependencies get added,
+// at a cost of up to a linear time per dependency; any other mutation drops it,
+// so that the next query builds it from scratch.
+// The closure takes a quadratic amount of memory: one bit per pair of nodes.
+// A frozen graph keeps the closure it had when being frozen, building it if needed.
+func WithReachabilityCache[T comparable]() Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.cacheReach = true
+	}
+}
+
+// WithAutoNormalize makes the validation, and therefore every resolution, normalize the graph first,
+// as Normalize does: dependencies of nodes on themselves, and dependencies on nodes
+// that are neither present in the graph nor supplied by the node provider, are removed
+// instead of making the resolution fail; the unknown dependency handler is not consulted for them.
+// This trades strictness for a best-effort order when the input is known to be messy.
+// Frozen graphs cannot be modified, so they are never normalized automatically.
+func WithAutoNormalize[T comparable]() Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.autoNormalize = true
+	}
+}
+
+// WithCategoryOrder groups the elements into categories, as returned by categoryOf,
+// and makes the resolution break the ties between free elements by the order of their categories first,
+// and only then by the insertion order; categories, which are not listed in the order, go last.
+// Dependencies are still honored, so an element may precede an element of an earlier category
+// whenever the latter depends on it. Pinned elements still go before all the others.
+// The categoryOf function is called many times during the resolution, so it should be cheap.
+func WithCategoryOrder[T comparable](categoryOf func(T) int, order []int) Option[T] {
+	return func(dg *DependencyGraph[T]) {
+		dg.categoryOf = categoryOf
+		dg.categoryRanks = make(map[int]int, len(order))
+
+		for _, category := range order {
+			if _, ok := dg.categoryRanks[category]; !ok {
+				dg.categoryRanks[category] = len(dg.categoryRanks)
+			}
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package depgraph
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestUnknownHandler tests that the unknown dependency handler is able to either
+// skip unknown dependencies or abort the resolution.
+func TestUnknownHandler(t *testing.T) {
+	errForbidden := errors.New("forbidden")
+
+	unknown := [][2]string{}
+	dg := NewDependencyGraph(WithUnknownHandler(func(node, dep string) error {
+		unknown = append(unknown, [2]string{node, dep})
+		if dep == "Z" {
+			return errForbidden
+		}
+
+		return nil
+	}))
+
+	dg.Add("A", "X")
+	dg.Add("B", "A")
+	dg.Add("C", "Y", "B")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with unknown handler: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B", "C"}) {
+		t.Fatalf("graph with unknown handler resolved incorrectly: %v", res)
+	}
+
+	if !slices.Equal(unknown, [][2]string{{"A", "X"}, {"C", "Y"}}) {
+		t.Fatalf("unknown handler called with unexpected arguments: %v", unknown)
+	}
+
+	dg.Add("D", "Z")
+
+	_, err = dg.Resolve()
+	if !errors.Is(err, errForbidden) {
+		t.Fatalf("resolved graph with a forbidden unknown dependency: %v", err)
+	}
+}
+
+// TestLogger tests that the resolution gets traced by the logger.
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		},
+	}))
+
+	dg := NewDependencyGraph(WithLogger[string](logger))
+	dg.Add("B", "A")
+	dg.Add("A")
+
+	_, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with logger: %v", err)
+	}
+
+	expected := `level=DEBUG msg="promoting dependency graph node" node=A satisfied=0
+level=DEBUG msg="resolving dependency graph node" node=A position=0
+level=DEBUG msg="promoting dependency graph node" node=B satisfied=1
+level=DEBUG msg="resolving dependency graph node" node=B position=1
+`
+
+	if buf.String() != expected {
+		t.Fatalf("resolution traced incorrectly:\n%s", buf.String())
+	}
+}
+
+// TestRejectDuplicateArgs tests that duplicate arguments are rejected only within a single call.
+func TestRejectDuplicateArgs(t *testing.T) {
+	dg := NewDependencyGraph(WithRejectDuplicateArgs[string]())
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("B", "A")
+
+	func() {
+		defer func() {
+			err, ok := recover().(error)
+			if !ok || !errors.Is(err, ErrDuplicateDependency) {
+				t.Fatalf("unexpected panic when adding duplicate arguments: %v", err)
+			}
+		}()
+
+		dg.Add("C", "A", "B", "A")
+	}()
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with rejected duplicates: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("graph with rejected duplicates resolved incorrectly: %v", res)
+	}
+}
+
+// TestNodeProvider tests that unknown dependencies get supplied by the node provider recursively.
+func TestNodeProvider(t *testing.T) {
+	known := map[string][]string{
+		"lib":  {"core", "util"},
+		"core": {},
+		"util": {"core"},
+	}
+
+	requested := []string{}
+	dg := NewDependencyGraph(WithNodeProvider(func(dep string) ([]string, bool) {
+		requested = append(requested, dep)
+		deps, ok := known[dep]
+
+		return deps, ok
+	}))
+
+	dg.Add("app", "lib")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with node provider: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"core", "util", "lib", "app"}) {
+		t.Fatalf("graph with node provider resolved incorrectly: %v", res)
+	}
+
+	if !slices.Equal(requested, []string{"lib", "core", "util"}) {
+		t.Fatalf("node provider called with unexpected arguments: %v", requested)
+	}
+
+	dg.Add("app", "missing")
+
+	_, err = dg.Resolve()
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("resolved graph with a dependency unknown to the node provider: %v", err)
+	}
+}
+
+// TestRequireConnected tests that disconnected graphs are rejected.
+func TestRequireConnected(t *testing.T) {
+	dg := NewDependencyGraph(WithRequireConnected[string]())
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "C")
+	dg.Add("E")
+
+	_, err := dg.Resolve()
+	if !errors.Is(err, ErrDisconnectedGraph) {
+		t.Fatalf("resolved disconnected graph: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), `graph has 3 components, represented by "A", "C", "E"`) {
+		t.Fatalf("unexpected error when resolving disconnected graph: %v", err)
+	}
+
+	dg.Add("C", "B")
+	dg.Add("E", "D")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving connected graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B", "C", "D", "E"}) {
+		t.Fatalf("connected graph resolved incorrectly: %v", res)
+	}
+}
+
+// TestInPlaceResolve tests that, by default, a resolution does not affect the order of later resolutions,
+// whereas the in-place resolution does.
+func TestInPlaceResolve(t *testing.T) {
+	build := func(opts ...Option[string]) *DependencyGraph[string] {
+		dg := NewDependencyGraph(opts...)
+		dg.Add("A", "D")
+		dg.Add("B", "C", "D")
+		dg.Add("C", "D")
+		dg.Add("D")
+
+		return dg
+	}
+
+	extend := func(dg *DependencyGraph[string]) []string {
+		dg.Add("F")
+		dg.Add("G", "F")
+
+		return dg.MustResolve()
+	}
+
+	expected := extend(build())
+	if !slices.Equal(expected, []string{"D", "F", "C", "A", "G", "B"}) {
+		t.Fatalf("graph resolved incorrectly: %v", expected)
+	}
+
+	dg := build()
+	dg.MustResolve()
+
+	if res := extend(dg); !slices.Equal(res, expected) {
+		t.Fatalf("earlier resolution affected the order: %v", res)
+	}
+
+	dg = build(WithInPlaceResolve[string]())
+	dg.MustResolve()
+
+	if res := extend(dg); !slices.Equal(res, []string{"D", "F", "A", "C", "G", "B"}) {
+		t.Fatalf("graph resolved in-place incorrectly: %v", res)
+	}
+}
+
+// TestAutoNormalize tests that self-loops and dangling dependencies get removed before the resolution.
+func TestAutoNormalize(t *testing.T) {
+	dg := NewDependencyGraph(WithAutoNormalize[string](), WithNodeProvider(func(dep string) ([]string, bool) {
+		return nil, dep == "P"
+	}))
+	dg.Add("A", "A", "X")
+	dg.Add("B", "A", "P", "Y")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with automatic normalization: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "P", "B"}) {
+		t.Fatalf("graph with automatic normalization resolved incorrectly: %v", res)
+	}
+
+	if deps := dg.Dependencies("B"); !slices.Equal(deps, []string{"A", "P"}) {
+		t.Fatalf("dependencies normalized incorrectly: %v", deps)
+	}
+
+	dg = NewDependencyGraph[string]()
+	dg.Add("A", "A")
+
+	_, err = dg.Resolve()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("graph normalized without the option: %v", err)
+	}
+}
+
+// TestCategoryOrder tests that free elements are ordered by their categories first.
+func TestCategoryOrder(t *testing.T) {
+	const (
+		migration = iota
+		service
+		cache
+	)
+
+	categories := map[string]int{
+		"redis": cache, "api": service, "worker": service,
+		"users-v1": migration, "users-v2": migration, "audit": 99,
+	}
+
+	dg := NewDependencyGraph(WithCategoryOrder(func(node string) int {
+		return categories[node]
+	}, []int{migration, service, cache}))
+	dg.Add("redis")
+	dg.Add("api", "redis")
+	dg.Add("users-v1")
+	dg.Add("audit")
+	dg.Add("worker")
+	dg.Add("users-v2", "users-v1")
+	dg.Pin("audit")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with category order: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"audit", "users-v1", "users-v2", "worker", "redis", "api"}) {
+		t.Fatalf("graph with category order resolved incorrectly: %v", res)
+	}
+
+	// The categories, which are not listed, go after all the listed ones.
+	dg = NewDependencyGraph(WithCategoryOrder(func(node string) int {
+		return categories[node]
+	}, []int{migration, service, cache}))
+	dg.Add("audit")
+	dg.Add("redis")
+
+	if res := dg.MustResolve(); !slices.Equal(res, []string{"redis", "audit"}) {
+		t.Fatalf("graph with unlisted category resolved incorrectly: %v", res)
+	}
+}
@@ -0,0 +1,104 @@
+package depgraph
+
+import (
+	"fmt"
+	"iter"
+)
+
+// AllOrderings returns an iterator that lazily yields every distinct valid resolution order
+// of the graph, starting with the orderings that follow the insertion order most closely.
+// The number of orderings grows combinatorially with the number of independent elements,
+// so callers should be prepared to stop early.
+// Each yielded slice is freshly allocated and may be retained by the caller.
+// Errors are handled in the same way as in ResolveIter: if the graph is invalid
+// or contains a circular dependency, the iterator yields a pair of (nil, *ResolveError) and stops.
+func (dg *DependencyGraph[T]) AllOrderings() iter.Seq2[[]T, error] {
+	return func(yield func([]T, error) bool) {
+		err := dg.Validate()
+		if err != nil {
+			yield(nil, &ResolveError{
+				Total: len(dg.edges),
+				Err:   fmt.Errorf("validating dependency graph: %w", err),
+			})
+
+			return
+		}
+
+		edges := dg.edges
+		refcounts, dependents := dg.adjacency()
+
+		n := len(edges)
+		used := make([]bool, n)
+		order := make([]int, n)
+
+		// cursor[depth] holds the next edge index to try at the given depth of the search.
+		// The search is done with an explicit stack, so that deep graphs do not exhaust the call stack.
+		cursor := make([]int, n+1)
+		depth := 0
+		found := false
+
+		for {
+			if depth == n {
+				found = true
+				res := make([]T, n)
+
+				for i, idx := range order {
+					res[i] = edges[idx].name
+				}
+
+				if !yield(res, nil) {
+					return
+				}
+			} else {
+				// Pick the next free edge at this depth.
+				next := -1
+				for i := cursor[depth]; i < n; i++ {
+					if !used[i] && refcounts[i] == 0 {
+						next = i
+						break
+					}
+				}
+
+				if next >= 0 {
+					cursor[depth] = next + 1
+					used[next] = true
+					order[depth] = next
+
+					for _, d := range dependents[next] {
+						refcounts[d]--
+					}
+
+					depth++
+					cursor[depth] = 0
+
+					continue
+				}
+
+				// In an acyclic graph, there is always at least one free edge,
+				// so getting stuck before any ordering has been found means there is a cycle.
+				if !found {
+					yield(nil, &ResolveError{
+						Resolved: depth,
+						Total:    n,
+						Err:      ErrCircularDependency,
+					})
+
+					return
+				}
+			}
+
+			// Backtrack, undoing the last choice.
+			if depth == 0 {
+				return
+			}
+
+			depth--
+			last := order[depth]
+			used[last] = false
+
+			for _, d := range dependents[last] {
+				refcounts[d]++
+			}
+		}
+	}
+}
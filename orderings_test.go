@@ -0,0 +1,103 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestAllOrderings tests that all valid orderings get enumerated, and that errors get reported.
+func TestAllOrderings(t *testing.T) {
+	tbl := []struct {
+		in       [][]string // [0]: element; [1:]: element's dependencies
+		out      [][]string
+		circular bool
+		unknown  bool
+	}{
+		{
+			in:  [][]string{},
+			out: [][]string{{}},
+		},
+		{
+			in:  [][]string{{"A"}, {"B", "A"}},
+			out: [][]string{{"A", "B"}},
+		},
+		{
+			in:  [][]string{{"A"}, {"B"}, {"C", "A"}},
+			out: [][]string{{"A", "B", "C"}, {"A", "C", "B"}, {"B", "A", "C"}},
+		},
+		{
+			in:  [][]string{{"D", "B", "C"}, {"B", "A"}, {"C", "A"}, {"A"}},
+			out: [][]string{{"A", "B", "C", "D"}, {"A", "C", "B", "D"}},
+		},
+		{
+			in:       [][]string{{"A"}, {"B", "C"}, {"C", "B"}},
+			circular: true,
+		},
+		{
+			in:      [][]string{{"A", "X"}},
+			unknown: true,
+		},
+	}
+
+	for _, test := range tbl {
+		dg := NewDependencyGraph[string]()
+
+		for _, in := range test.in {
+			dg.Add(in[0], in[1:]...)
+		}
+
+		res := [][]string{}
+		var resErr error
+
+		for order, err := range dg.AllOrderings() {
+			if err != nil {
+				resErr = err
+				break
+			}
+
+			res = append(res, order)
+		}
+
+		if test.circular != errors.Is(resErr, ErrCircularDependency) || test.unknown != errors.Is(resErr, ErrUnknownDependency) {
+			t.Fatalf("unexpected error when enumerating orderings: input = %v: %v", test.in, resErr)
+		}
+
+		var typed *ResolveError
+		if resErr != nil && !errors.As(resErr, &typed) {
+			t.Fatalf("error when enumerating orderings is not a *ResolveError: input = %v: %v", test.in, resErr)
+		}
+
+		if test.circular && typed.Resolved != 1 {
+			t.Fatalf("unexpected number of resolved elements when enumerating orderings: %d", typed.Resolved)
+		}
+
+		if resErr == nil && !slices.EqualFunc(res, test.out, slices.Equal) {
+			t.Fatalf("orderings enumerated incorrectly: input = %v; output = %v; expected = %v", test.in, res, test.out)
+		}
+	}
+}
+
+// TestAllOrderingsEarlyExit tests that the enumeration may be stopped early.
+func TestAllOrderingsEarlyExit(t *testing.T) {
+	dg := NewDependencyGraph[int]()
+	for i := range 10 {
+		dg.Add(i)
+	}
+
+	count := 0
+	for _, err := range dg.AllOrderings() {
+		if err != nil {
+			t.Fatalf("enumerating orderings: %v", err)
+		}
+
+		count++
+		if count == 3 {
+			break
+		}
+	}
+
+	if count != 3 {
+		t.Fatalf("enumeration has not stopped early: %d", count)
+	}
+}
@@ -0,0 +1,32 @@
+package depgraph
+
+// Pin marks nodes as pinned: whenever a pinned node becomes free, it is resolved before
+// all free nodes that are not pinned, regardless of the insertion order.
+// Dependencies are still honored: a pinned node with unmet dependencies keeps waiting for them.
+// Free pinned nodes are resolved in the insertion order among themselves.
+// Nodes may be pinned before they are added to the graph.
+func (dg *DependencyGraph[T]) Pin(nodes ...T) {
+	dg.checkFrozen()
+
+	if dg.pinned == nil {
+		dg.pinned = make(map[T]struct{}, len(nodes))
+	}
+
+	for _, node := range nodes {
+		dg.pinned[node] = struct{}{}
+	}
+}
+
+// pickPinned moves the first pinned edge of the free list to its front,
+// shifting the preceding edges to keep their relative order.
+// If there are no pinned edges in the free list, it is left intact.
+func (dg *DependencyGraph[T]) pickPinned(free []*depEdge[T]) {
+	for i, edge := range free {
+		if _, ok := dg.pinned[edge.name]; ok {
+			copy(free[1:i+1], free[:i])
+			free[0] = edge
+
+			return
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package depgraph
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestPin tests that pinned nodes are resolved first as soon as they become free.
+func TestPin(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C", "A")
+	dg.Add("D")
+	dg.Add("E", "B")
+	dg.Add("F")
+	dg.Pin("D", "C", "E")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with pinned nodes: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"D", "A", "C", "B", "E", "F"}) {
+		t.Fatalf("graph with pinned nodes resolved incorrectly: %v", res)
+	}
+}
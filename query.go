@@ -0,0 +1,351 @@
+package depgraph
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Ranks resolves the graph and returns the position of each element in the resolution order,
+// as returned by Resolve.
+// This allows checking whether an element comes before another one in a constant time.
+func (dg *DependencyGraph[T]) Ranks() (map[T]int, error) {
+	res, err := dg.Resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[T]int, len(res))
+	for i, el := range res {
+		ranks[el] = i
+	}
+
+	return ranks, nil
+}
+
+// OrderDiff compares two resolution orders of the same graph, such as the ones before and after
+// changing its edges, and returns the signed change in position of every element present in both:
+// a positive value means that the element has moved later in b by this many positions.
+// Elements present in only one of the orders are omitted.
+func OrderDiff[T comparable](a, b []T) map[T]int {
+	pos := make(map[T]int, len(a))
+	for i, el := range a {
+		pos[el] = i
+	}
+
+	res := make(map[T]int, min(len(a), len(b)))
+
+	for i, el := range b {
+		if j, ok := pos[el]; ok {
+			res[el] = i - j
+		}
+	}
+
+	return res
+}
+
+// EquivalentNodes groups the nodes, which depend on exactly the same set of nodes,
+// regardless of the order in which their dependencies have been added.
+// Such nodes are candidates for merging. Nodes without dependencies are considered equivalent as well.
+// Only groups of at least two nodes are returned; the groups, as well as the nodes in each group,
+// follow the edge list order.
+func (dg *DependencyGraph[T]) EquivalentNodes() [][]T {
+	// Refer to every node, including unknown dependencies, by a dense id,
+	// so that a dependency set can be encoded into a comparable key.
+	ids := make(map[T]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		ids[edge.name] = i
+	}
+
+	groups := map[string]int{}
+	res := [][]T{}
+	depIDs := []int{}
+
+	for _, edge := range dg.edges {
+		depIDs = depIDs[:0]
+		for _, dep := range edge.depOrder {
+			id, ok := ids[dep]
+			if !ok {
+				id = len(ids)
+				ids[dep] = id
+			}
+
+			depIDs = append(depIDs, id)
+		}
+
+		slices.Sort(depIDs)
+
+		key := []byte{}
+		for _, id := range depIDs {
+			key = binary.AppendUvarint(key, uint64(id))
+		}
+
+		group, ok := groups[string(key)]
+		if !ok {
+			group = len(res)
+			groups[string(key)] = group
+			res = append(res, nil)
+		}
+
+		res[group] = append(res[group], edge.name)
+	}
+
+	return slices.DeleteFunc(res, func(group []T) bool {
+		return len(group) < 2
+	})
+}
+
+// InterchangeableGroups groups the nodes, which have identical sets of transitive dependencies,
+// and are directly depended upon by identical sets of nodes.
+// Such nodes always become free at the same time and unlock the same nodes,
+// so a scheduler may process them in any order, or balance them across workers freely.
+// Unknown dependencies are not accounted for.
+// Only groups of at least two nodes are returned; the groups, as well as the nodes in each group,
+// follow the edge list order.
+func (dg *DependencyGraph[T]) InterchangeableGroups() [][]T {
+	_, dependents := dg.adjacency()
+	deps := dg.depIndices()
+
+	n := len(dg.edges)
+	visited := make([]int, n)
+	closure := []int{}
+	stack := []int{}
+
+	groups := map[string]int{}
+	res := [][]T{}
+
+	for i, edge := range dg.edges {
+		// Collect the transitive dependencies, using i+1 as the visit stamp.
+		closure = closure[:0]
+		stack = append(stack[:0], deps[i]...)
+
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if visited[cur] == i+1 {
+				continue
+			}
+
+			visited[cur] = i + 1
+			closure = append(closure, cur)
+			stack = append(stack, deps[cur]...)
+		}
+
+		slices.Sort(closure)
+
+		// The dependents are already listed in the edge list order.
+		key := binary.AppendUvarint(nil, uint64(len(closure)))
+		for _, id := range closure {
+			key = binary.AppendUvarint(key, uint64(id))
+		}
+
+		for _, id := range dependents[i] {
+			key = binary.AppendUvarint(key, uint64(id))
+		}
+
+		group, ok := groups[string(key)]
+		if !ok {
+			group = len(res)
+			groups[string(key)] = group
+			res = append(res, nil)
+		}
+
+		res[group] = append(res[group], edge.name)
+	}
+
+	return slices.DeleteFunc(res, func(group []T) bool {
+		return len(group) < 2
+	})
+}
+
+// Independent reports whether neither of two nodes depends on the other, either directly or transitively,
+// meaning that they may be processed concurrently.
+// A node is never independent of itself.
+// An error is returned if either of the nodes is not present in the graph.
+func (dg *DependencyGraph[T]) Independent(a, b T) (bool, error) {
+	for _, name := range []T{a, b} {
+		if _, ok := dg.edgeMap[name]; !ok {
+			return false, fmt.Errorf("looking up node \"%v\": %w", name, ErrUnknownNode)
+		}
+	}
+
+	return !dg.reaches(a, b) && !dg.reaches(b, a), nil
+}
+
+// DependenciesIter returns an iterator that lazily yields the direct dependencies of a node,
+// in the order in which they have been added.
+// If the node is not present in the graph, the iterator yields nothing.
+// The graph must not be mutated during the iteration.
+func (dg *DependencyGraph[T]) DependenciesIter(name T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		edge, ok := dg.edgeMap[name]
+		if !ok {
+			return
+		}
+
+		for _, dep := range edge.depOrder {
+			if !yield(dep) {
+				return
+			}
+		}
+	}
+}
+
+// Dependencies returns the direct dependencies of a node, in the order in which they have been added.
+// If the node is not present in the graph, it returns nil.
+func (dg *DependencyGraph[T]) Dependencies(name T) []T {
+	edge, ok := dg.edgeMap[name]
+	if !ok {
+		return nil
+	}
+
+	return slices.Clone(edge.depOrder)
+}
+
+// EffectiveDependencies returns the direct dependencies of a node, which the resolution honors
+// at the moment, in the order in which they have been added.
+// The node may also be referred to by any of its aliases; capability dependencies are included.
+// The dependencies, which are not present in the graph, are left out: this covers weak dependencies
+// on absent nodes, as well as unknown dependencies let through by the unknown dependency handler
+// or dropped by the automatic normalization, since neither of them constrains the order.
+// The graph is not validated, so the nodes, which a node provider would supply, are left out as well.
+// If the node is not present in the graph, it returns nil.
+func (dg *DependencyGraph[T]) EffectiveDependencies(name T) []T {
+	edge, ok := dg.edgeMap[dg.resolveAlias(name)]
+	if !ok {
+		return nil
+	}
+
+	res := []T{}
+	for _, dep := range edge.depOrder {
+		if _, ok := dg.edgeMap[dep]; ok {
+			res = append(res, dep)
+		}
+	}
+
+	return res
+}
+
+// Frontier returns the nodes that are immediately runnable, given a set of already completed nodes:
+// the ones which are not completed yet, but all of whose dependencies are.
+// The nodes are returned in the edge list order.
+func (dg *DependencyGraph[T]) Frontier(done map[T]struct{}) []T {
+	res := []T{}
+
+outer:
+	for _, edge := range dg.edges {
+		if _, ok := done[edge.name]; ok {
+			continue
+		}
+
+		for _, dep := range edge.depOrder {
+			if _, ok := done[dep]; !ok {
+				continue outer
+			}
+		}
+
+		res = append(res, edge.name)
+	}
+
+	return res
+}
+
+// NodesAtDistance returns the nodes, whose shortest distance from a node, measured in dependency edges,
+// is exactly k; unknown dependencies are included as well.
+// With k equal to zero, only the node itself is returned.
+// The nodes are returned in the breadth-first order, following the order in which
+// the dependencies have been added.
+// If the node is not present in the graph, or k is negative, nil is returned.
+func (dg *DependencyGraph[T]) NodesAtDistance(from T, k int) []T {
+	if _, ok := dg.edgeMap[from]; !ok || k < 0 {
+		return nil
+	}
+
+	visited := map[T]struct{}{from: {}}
+	level := []T{from}
+
+	for range k {
+		next := []T{}
+
+		for _, cur := range level {
+			edge, ok := dg.edgeMap[cur]
+			if !ok {
+				continue
+			}
+
+			for _, dep := range edge.depOrder {
+				if _, ok := visited[dep]; !ok {
+					visited[dep] = struct{}{}
+					next = append(next, dep)
+				}
+			}
+		}
+
+		if len(next) == 0 {
+			return next
+		}
+
+		level = next
+	}
+
+	return level
+}
+
+// BFS walks the graph breadth-first, starting from its roots, which are the nodes no other node depends on,
+// and following the dependencies level by level; unknown dependencies are visited as well.
+// Every node is visited once, at its shortest distance from any of the roots, which is passed as level.
+// Within a level, the nodes follow the edge list order of the roots,
+// and then the order in which the dependencies have been added.
+// If visit returns false, the walk stops.
+// An error is returned if some nodes cannot be reached from any root,
+// which only happens when they are a part of a circular dependency; such nodes are not visited.
+func (dg *DependencyGraph[T]) BFS(visit func(node T, level int) bool) error {
+	_, dependents := dg.adjacency()
+
+	visited := make(map[T]struct{}, len(dg.edges))
+	level := []T{}
+
+	for i, edge := range dg.edges {
+		if len(dependents[i]) == 0 {
+			visited[edge.name] = struct{}{}
+			level = append(level, edge.name)
+		}
+	}
+
+	reached := 0
+
+	for depth := 0; len(level) > 0; depth++ {
+		next := []T{}
+
+		for _, cur := range level {
+			if !visit(cur, depth) {
+				return nil
+			}
+
+			edge, ok := dg.edgeMap[cur]
+			if !ok {
+				continue
+			}
+
+			reached++
+
+			for _, dep := range edge.depOrder {
+				if _, ok := visited[dep]; !ok {
+					visited[dep] = struct{}{}
+					next = append(next, dep)
+				}
+			}
+		}
+
+		level = next
+	}
+
+	if reached != len(dg.edges) {
+		return fmt.Errorf("walking dependency graph: %d of %d elements are unreachable: %w",
+			len(dg.edges)-reached, len(dg.edges), ErrCircularDependency)
+	}
+
+	return nil
+}
@@ -0,0 +1,323 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+// TestRanks tests that the ranks match the resolution order.
+func TestRanks(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "B", "A")
+	dg.Add("E")
+
+	ranks, err := dg.Ranks()
+	if err != nil {
+		t.Fatalf("computing graph ranks: %v", err)
+	}
+
+	if !maps.Equal(ranks, map[string]int{"A": 0, "C": 1, "E": 2, "B": 3, "D": 4}) {
+		t.Fatalf("graph ranks computed incorrectly: %v", ranks)
+	}
+
+	dg.Add("A", "D")
+
+	_, err = dg.Ranks()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("computed ranks of a graph with circular dependency: %v", err)
+	}
+}
+
+// TestOrderDiff tests the position changes between two resolutions.
+func TestOrderDiff(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C")
+	dg.Add("D", "A")
+
+	before := dg.MustResolve()
+
+	dg.Add("B", "C")
+	dg.Add("E")
+
+	after := dg.MustResolve()
+
+	diff := OrderDiff(before, after)
+	if !maps.Equal(diff, map[string]int{"A": 0, "B": 3, "C": -1, "D": 0}) {
+		t.Fatalf("order difference computed incorrectly: %v, %v -> %v", diff, before, after)
+	}
+}
+
+// TestEquivalentNodes tests that nodes get grouped by their dependency sets.
+func TestEquivalentNodes(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C", "A", "B")
+	dg.Add("D", "A")
+	dg.Add("E", "B")
+	dg.Add("E", "A")
+	dg.Add("F", "X")
+	dg.Add("G", "A", "X")
+	dg.Add("H", "X")
+
+	res := dg.EquivalentNodes()
+	if !slices.EqualFunc(res, [][]string{{"A", "B"}, {"C", "E"}, {"F", "H"}}, slices.Equal) {
+		t.Fatalf("equivalent nodes grouped incorrectly: %v", res)
+	}
+}
+
+// TestIndependent tests that nodes are independent only when neither reaches the other.
+func TestIndependent(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "B")
+	dg.Add("D", "A")
+
+	tbl := []struct {
+		a, b        string
+		independent bool
+	}{
+		{a: "A", b: "A", independent: false},
+		{a: "A", b: "C", independent: false},
+		{a: "C", b: "A", independent: false},
+		{a: "B", b: "D", independent: true},
+		{a: "C", b: "D", independent: true},
+	}
+
+	for _, test := range tbl {
+		independent, err := dg.Independent(test.a, test.b)
+		if err != nil {
+			t.Fatalf("checking independence of %v and %v: %v", test.a, test.b, err)
+		}
+
+		if independent != test.independent {
+			t.Fatalf("independence of %v and %v computed incorrectly: %v", test.a, test.b, independent)
+		}
+	}
+
+	_, err := dg.Independent("A", "X")
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("checked independence of an unknown node: %v", err)
+	}
+}
+
+// TestDependencies tests that direct dependencies are listed in their insertion order,
+// and that the iterator allows us to exit early.
+func TestDependencies(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "D", "B")
+	dg.Add("A", "C", "B", "E")
+
+	if deps := dg.Dependencies("A"); !slices.Equal(deps, []string{"D", "B", "C", "E"}) {
+		t.Fatalf("dependencies listed incorrectly: %v", deps)
+	}
+
+	if deps := dg.Dependencies("X"); deps != nil {
+		t.Fatalf("dependencies of an unknown node listed: %v", deps)
+	}
+
+	deps := []string{}
+	for dep := range dg.DependenciesIter("A") {
+		deps = append(deps, dep)
+		if dep == "C" {
+			break
+		}
+	}
+
+	if !slices.Equal(deps, []string{"D", "B", "C"}) {
+		t.Fatalf("dependencies iterated incorrectly: %v", deps)
+	}
+
+	for dep := range dg.DependenciesIter("X") {
+		t.Fatalf("dependencies of an unknown node iterated: %v", dep)
+	}
+}
+
+// TestEffectiveDependencies tests that only the dependencies constraining the order are returned,
+// with aliases and capabilities taken into account.
+func TestEffectiveDependencies(t *testing.T) {
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		return nil
+	}))
+
+	err := dg.Alias("db", "database")
+	if err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+
+	dg.AddConsumer("app", "logging")
+	dg.Add("app", "database", "X")
+	dg.AddWeak("app", "metrics")
+	dg.AddWeak("app", "cache")
+	dg.Add("db")
+	dg.Add("cache")
+	dg.AddProvider("syslog", "logging")
+
+	if res := dg.EffectiveDependencies("app"); !slices.Equal(res, []string{"db", "cache", "syslog"}) {
+		t.Fatalf("effective dependencies computed incorrectly: %v", res)
+	}
+
+	if res := dg.Dependencies("app"); !slices.Equal(res, []string{"db", "X", "metrics", "cache", "syslog"}) {
+		t.Fatalf("dependencies computed incorrectly: %v", res)
+	}
+
+	dg.Add("database", "cache")
+
+	if res := dg.EffectiveDependencies("database"); !slices.Equal(res, []string{"cache"}) {
+		t.Fatalf("effective dependencies of an alias computed incorrectly: %v", res)
+	}
+
+	if res := dg.EffectiveDependencies("X"); res != nil {
+		t.Fatalf("computed effective dependencies of an unknown node: %v", res)
+	}
+}
+
+// TestFrontier tests that the frontier contains exactly the runnable nodes.
+func TestFrontier(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "B", "C")
+	dg.Add("E", "X")
+
+	tbl := []struct {
+		done []string
+		out  []string
+	}{
+		{done: []string{}, out: []string{"A", "C"}},
+		{done: []string{"A"}, out: []string{"B", "C"}},
+		{done: []string{"A", "B"}, out: []string{"C"}},
+		{done: []string{"A", "B", "C"}, out: []string{"D"}},
+		{done: []string{"A", "B", "C", "D", "X"}, out: []string{"E"}},
+	}
+
+	for _, test := range tbl {
+		done := map[string]struct{}{}
+		for _, el := range test.done {
+			done[el] = struct{}{}
+		}
+
+		res := dg.Frontier(done)
+		if !slices.Equal(res, test.out) {
+			t.Fatalf("frontier computed incorrectly: done = %v; output = %v; expected = %v", test.done, res, test.out)
+		}
+	}
+}
+
+// TestInterchangeableGroups tests that the nodes with identical transitive dependencies
+// and identical dependents are grouped together.
+func TestInterchangeableGroups(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("base")
+	dg.Add("lib")
+	dg.Add("test-1", "base", "lib")
+	dg.Add("test-2", "lib", "base")
+	dg.Add("test-4", "mid")
+	dg.Add("mid", "base", "lib")
+	dg.Add("report", "test-1", "test-2", "test-4")
+	dg.Add("extra", "test-2")
+
+	groups := dg.InterchangeableGroups()
+
+	// test-1 and test-4 have the same dependents, but test-4 also depends on mid;
+	// test-2 has an extra dependent.
+	expected := [][]string{{"base", "lib"}}
+	if !slices.EqualFunc(groups, expected, slices.Equal) {
+		t.Fatalf("interchangeable groups computed incorrectly: %v", groups)
+	}
+
+	dg.Add("extra", "test-1")
+
+	groups = dg.InterchangeableGroups()
+	expected = [][]string{{"base", "lib"}, {"test-1", "test-2"}}
+
+	if !slices.EqualFunc(groups, expected, slices.Equal) {
+		t.Fatalf("interchangeable groups computed incorrectly: %v", groups)
+	}
+}
+
+// TestNodesAtDistance tests that the nodes are grouped by their shortest distance.
+func TestNodesAtDistance(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("core", "net", "log")
+	dg.Add("net", "tls", "log")
+	dg.Add("log", "fmt")
+	dg.Add("tls", "crypto")
+
+	tbl := []struct {
+		k        int
+		expected []string
+	}{
+		{0, []string{"core"}},
+		{1, []string{"net", "log"}},
+		{2, []string{"tls", "fmt"}},
+		{3, []string{"crypto"}},
+		{4, []string{}},
+	}
+
+	for _, tc := range tbl {
+		if res := dg.NodesAtDistance("core", tc.k); !slices.Equal(res, tc.expected) {
+			t.Fatalf("nodes at distance %d listed incorrectly: %v", tc.k, res)
+		}
+	}
+
+	if res := dg.NodesAtDistance("X", 0); res != nil {
+		t.Fatalf("nodes listed for an unknown node: %v", res)
+	}
+}
+
+// TestBFS tests the breadth-first walk from the roots, including stopping it early.
+func TestBFS(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("lib", "base")
+	dg.Add("app", "lib", "log")
+	dg.Add("log", "base")
+	dg.Add("base")
+	dg.Add("tool", "base", "X")
+
+	visited := []string{}
+
+	err := dg.BFS(func(node string, level int) bool {
+		visited = append(visited, fmt.Sprintf("%s:%d", node, level))
+		return true
+	})
+	if err != nil {
+		t.Fatalf("walking graph breadth-first: %v", err)
+	}
+
+	if !slices.Equal(visited, []string{"app:0", "tool:0", "lib:1", "log:1", "base:1", "X:1"}) {
+		t.Fatalf("graph walked breadth-first incorrectly: %v", visited)
+	}
+
+	visited = visited[:0]
+
+	err = dg.BFS(func(node string, level int) bool {
+		visited = append(visited, node)
+		return level == 0
+	})
+	if err != nil {
+		t.Fatalf("walking graph breadth-first: %v", err)
+	}
+
+	if !slices.Equal(visited, []string{"app", "tool", "lib"}) {
+		t.Fatalf("breadth-first walk stopped incorrectly: %v", visited)
+	}
+
+	dg.Add("C1", "C2")
+	dg.Add("C2", "C1")
+
+	err = dg.BFS(func(string, int) bool { return true })
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("unreachable cycle not reported: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package depgraph
+
+import (
+	"math/bits"
+	"slices"
+)
+
+// DependsOn reports whether name depends on dep, either directly or transitively.
+// A node never depends on itself, even if it is a part of a cycle.
+// If the graph has been created with WithReachabilityCache, it takes a constant time.
+func (dg *DependencyGraph[T]) DependsOn(name, dep T) bool {
+	return name != dep && dg.reaches(name, dep)
+}
+
+// reachCache is the transitive closure of the graph, which is kept up to date as dependencies
+// get added, so that reachability queries do not need to traverse the graph.
+type reachCache[T comparable] struct {
+	// index numbers every node, including the unknown dependencies.
+	index map[T]int
+
+	// rows holds, for every node, the bitset of the nodes reachable from it through at least one dependency.
+	// A row may be shorter than others, in which case the missing bits are unset.
+	rows [][]uint64
+}
+
+// buildReach computes the transitive closure of the graph from scratch.
+// The strongly connected components are processed in dependency order, so that each row
+// is assembled from the already completed rows of the dependencies.
+func (dg *DependencyGraph[T]) buildReach() *reachCache[T] {
+	rc := &reachCache[T]{
+		index: make(map[T]int, len(dg.edges)),
+		rows:  make([][]uint64, len(dg.edges)),
+	}
+
+	for i, edge := range dg.edges {
+		rc.index[edge.name] = i
+	}
+
+	comp := dg.stronglyConnected()
+
+	comps := 0
+	for _, c := range comp {
+		comps = max(comps, c+1)
+	}
+
+	members := make([][]int, comps)
+	for i, c := range comp {
+		members[c] = append(members[c], i)
+	}
+
+	for c := range comps {
+		row := []uint64{}
+		cyclic := len(members[c]) > 1
+
+		for _, i := range members[c] {
+			for _, dep := range dg.edges[i].depOrder {
+				d := rc.node(dep)
+				if d < len(comp) && comp[d] == c {
+					// Every member of a cycle reaches all the others, which is accounted for below;
+					// a single member only reaches itself through a self-loop.
+					cyclic = true
+					continue
+				}
+
+				setBit(&row, d)
+				orBits(&row, rc.rows[d])
+			}
+		}
+
+		if cyclic {
+			for _, i := range members[c] {
+				setBit(&row, i)
+			}
+		}
+
+		for _, i := range members[c] {
+			rc.rows[i] = slices.Clone(row)
+		}
+	}
+
+	return rc
+}
+
+// node returns the number of a node, numbering it first if needed.
+func (rc *reachCache[T]) node(name T) int {
+	i, ok := rc.index[name]
+	if !ok {
+		i = len(rc.rows)
+		rc.index[name] = i
+		rc.rows = append(rc.rows, nil)
+	}
+
+	return i
+}
+
+// reaches reports whether to is reachable from from through at least one dependency.
+func (rc *reachCache[T]) reaches(from, to T) bool {
+	i, ok := rc.index[from]
+	if !ok {
+		return false
+	}
+
+	j, ok := rc.index[to]
+
+	return ok && hasBit(rc.rows[i], j)
+}
+
+// add updates the closure with a new dependency of name on dep:
+// every node, which reaches name, now reaches dep along with everything reachable from dep.
+func (rc *reachCache[T]) add(name, dep T) {
+	u, v := rc.node(name), rc.node(dep)
+	if hasBit(rc.rows[u], v) {
+		return
+	}
+
+	// The row of dep may get updated by the loop itself, if dep reaches name.
+	reached := slices.Clone(rc.rows[v])
+	setBit(&reached, v)
+
+	for x, row := range rc.rows {
+		if x == u || hasBit(row, u) {
+			orBits(&rc.rows[x], reached)
+		}
+	}
+}
+
+// setBit sets the i-th bit of a bitset, growing it if needed.
+func setBit(set *[]uint64, i int) {
+	if w := i / 64; w >= len(*set) {
+		*set = append(*set, make([]uint64, w-len(*set)+1)...)
+	}
+
+	(*set)[i/64] |= 1 << (i % 64)
+}
+
+// hasBit reports whether the i-th bit of a bitset is set.
+func hasBit(set []uint64, i int) bool {
+	return i/64 < len(set) && set[i/64]&(1<<(i%64)) != 0
+}
+
+// orBits merges src into a bitset, growing it if needed.
+func orBits(set *[]uint64, src []uint64) {
+	if len(src) > len(*set) {
+		*set = append(*set, make([]uint64, len(src)-len(*set))...)
+	}
+
+	for w, word := range src {
+		(*set)[w] |= word
+	}
+}
+
+// nextBit returns the position of the first set bit of a bitset at or after from, or -1 if there is none.
+func nextBit(set []uint64, from int) int {
+	for w := from / 64; w < len(set); w++ {
+		word := set[w]
+		if w == from/64 {
+			word &= ^uint64(0) << (from % 64)
+		}
+
+		if word != 0 {
+			return w*64 + bits.TrailingZeros64(word)
+		}
+	}
+
+	return -1
+}
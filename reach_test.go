@@ -0,0 +1,131 @@
+package depgraph
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// TestDependsOn tests that transitive dependencies are reported, including the unknown ones,
+// and that a node never depends on itself.
+func TestDependsOn(t *testing.T) {
+	for _, cached := range []bool{false, true} {
+		opts := []Option[string]{}
+		if cached {
+			opts = append(opts, WithReachabilityCache[string]())
+		}
+
+		dg := NewDependencyGraph(opts...)
+		dg.Add("A")
+		dg.Add("B", "A")
+		dg.Add("C", "B", "X")
+		dg.Add("D", "D")
+
+		tbl := []struct {
+			name, dep string
+			depends   bool
+		}{
+			{name: "C", dep: "A", depends: true},
+			{name: "C", dep: "X", depends: true},
+			{name: "A", dep: "C", depends: false},
+			{name: "C", dep: "C", depends: false},
+			{name: "D", dep: "D", depends: false},
+			{name: "Y", dep: "A", depends: false},
+			{name: "C", dep: "Y", depends: false},
+		}
+
+		for _, test := range tbl {
+			if dg.DependsOn(test.name, test.dep) != test.depends {
+				t.Fatalf("dependency check of %v on %v is incorrect; cached = %v, expected %v",
+					test.name, test.dep, cached, test.depends)
+			}
+		}
+	}
+}
+
+// TestReachabilityCache tests that the cached closure stays in sync with the graph,
+// as it gets mutated in between the queries, and once it gets frozen.
+func TestReachabilityCache(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 5))
+
+	for range 20 {
+		plain := NewDependencyGraph[int]()
+		cached := NewDependencyGraph(WithReachabilityCache[int]())
+
+		// Nodes beyond 20 are never added, so they end up as unknown dependencies.
+		for step := range 100 {
+			name, dep := rng.IntN(20), rng.IntN(24)
+
+			switch op := rng.IntN(20); {
+			case op == 0:
+				plain.AddWeak(name, dep)
+				cached.AddWeak(name, dep)
+			case op == 1:
+				plain.Normalize()
+				cached.Normalize()
+			case op == 2:
+				if (plain.Alias(name, dep) == nil) != (cached.Alias(name, dep) == nil) {
+					t.Fatalf("aliasing %v to %v failed differently at step %d", dep, name, step)
+				}
+			default:
+				plain.Add(name, dep)
+				cached.Add(name, dep)
+			}
+
+			if step%10 != 0 {
+				continue
+			}
+
+			for a := range 24 {
+				for b := range 24 {
+					if cached.WouldCycle(a, b) != plain.WouldCycle(a, b) {
+						t.Fatalf("cached cycle check for edge %v -> %v is incorrect at step %d", a, b, step)
+					}
+				}
+			}
+		}
+
+		cached.Freeze()
+
+		for a := range 24 {
+			for b := range 24 {
+				if cached.DependsOn(a, b) != plain.DependsOn(a, b) {
+					t.Fatalf("cached dependency check of %v on %v is incorrect in a frozen graph", a, b)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkWouldCycle compares the cost of checking for cycles by traversing the graph,
+// which is the default, to checking them against the cached closure, while the graph grows.
+func BenchmarkWouldCycle(b *testing.B) {
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cached=%t", cached), func(b *testing.B) {
+			opts := []Option[int]{}
+			if cached {
+				opts = append(opts, WithReachabilityCache[int]())
+			}
+
+			dg := NewDependencyGraph(opts...)
+			src := GenerateRandomDAG(1000, 0.01, 1, func(i int) int {
+				return i
+			})
+
+			for _, edge := range src.edges {
+				dg.Add(edge.name, edge.depOrder...)
+			}
+
+			rng := rand.New(rand.NewPCG(1, 1))
+
+			b.ResetTimer()
+
+			for range b.N {
+				name, dep := rng.IntN(1000), rng.IntN(1000)
+				if !dg.WouldCycle(name, dep) {
+					dg.Add(name, dep)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package depgraph
+
+import "iter"
+
+// Resolver resolves a dependency graph repeatedly, retaining the buffers it works with
+// between resolutions, so that resolving the same graph, or a graph of a similar size,
+// over and over again does not allocate them anew each time.
+// A Resolver must not be used concurrently; use separate resolvers instead.
+type Resolver[T comparable] struct {
+	dg      *DependencyGraph[T]
+	scratch resolveScratch[T]
+
+	// done holds the elements, which have been yielded by the current resolution.
+	done map[T]struct{}
+}
+
+// NewResolver creates a new resolver for the graph.
+// The resolver always works with the current state of the graph.
+func (dg *DependencyGraph[T]) NewResolver() *Resolver[T] {
+	return &Resolver[T]{dg: dg}
+}
+
+// ResolveIter works like DependencyGraph.ResolveIter, reusing the resolver's buffers.
+// Only one resolution may be in progress at a time.
+func (r *Resolver[T]) ResolveIter() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if r.done == nil {
+			r.done = make(map[T]struct{}, len(r.dg.edges))
+		}
+
+		clear(r.done)
+
+		for el, err := range r.dg.resolveIter(&r.scratch) {
+			if err == nil {
+				r.done[el] = struct{}{}
+			}
+
+			if !yield(el, err) {
+				return
+			}
+		}
+	}
+}
+
+// Blockers returns the dependencies of a node, which have not been yielded yet
+// by the resolution in progress, or by the last one, in the order in which they have been added;
+// these are the dependencies the node is still waiting for.
+// Unknown dependencies are never blocking, since the resolution treats them as satisfied.
+// If the node is not present in the graph, nil is returned.
+func (r *Resolver[T]) Blockers(name T) []T {
+	edge, ok := r.dg.edgeMap[name]
+	if !ok {
+		return nil
+	}
+
+	res := []T{}
+
+	for _, dep := range edge.depOrder {
+		if _, known := r.dg.edgeMap[dep]; !known {
+			continue
+		}
+
+		if _, ok := r.done[dep]; !ok {
+			res = append(res, dep)
+		}
+	}
+
+	return res
+}
+
+// Resolve works like DependencyGraph.Resolve, reusing the resolver's buffers;
+// only the returned slice is allocated.
+func (r *Resolver[T]) Resolve() ([]T, error) {
+	res := make([]T, 0, len(r.dg.edges))
+
+	for el, err := range r.ResolveIter() {
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, el)
+	}
+
+	return res, nil
+}
+
+// UnlockedBy returns the nodes, which are only waiting for the given node, as reported by Blockers,
+// and thus become free the moment it gets resolved; this is the immediate payoff of resolving it next.
+// The nodes are returned in the edge list order.
+// If the node has already been resolved, or is not present in the graph, nil is returned.
+func (r *Resolver[T]) UnlockedBy(name T) []T {
+	if _, ok := r.dg.edgeMap[name]; !ok {
+		return nil
+	}
+
+	if _, ok := r.done[name]; ok {
+		return nil
+	}
+
+	res := []T{}
+
+	for _, edge := range r.dg.edges {
+		if _, ok := edge.deps[name]; !ok {
+			continue
+		}
+
+		if _, ok := r.done[edge.name]; ok {
+			continue
+		}
+
+		if blockers := r.Blockers(edge.name); len(blockers) == 1 {
+			res = append(res, edge.name)
+		}
+	}
+
+	return res
+}
+
+// Reset drops the references to the graph's elements held by the resolver's buffers,
+// while retaining their allocated capacity.
+// It does not have to be called between resolutions; it is useful when the resolver is kept around
+// and the elements should not be retained by it.
+func (r *Resolver[T]) Reset() {
+	clear(r.scratch.edges)
+	r.scratch.edges = r.scratch.edges[:0]
+	clear(r.scratch.refcounts)
+	clear(r.done)
+}
@@ -0,0 +1,155 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestResolver tests that the resolver yields the same order as the graph, across resets and changes.
+func TestResolver(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("B", "A")
+	dg.Add("A")
+	dg.Add("C")
+
+	r := dg.NewResolver()
+
+	for range 3 {
+		res, err := r.Resolve()
+		if err != nil {
+			t.Fatalf("resolving graph with resolver: %v", err)
+		}
+
+		if !slices.Equal(res, dg.MustResolve()) {
+			t.Fatalf("graph resolved with resolver incorrectly: %v", res)
+		}
+	}
+
+	r.Reset()
+	dg.Add("D", "C")
+	dg.Add("A", "D")
+
+	res, err := r.Resolve()
+	if err != nil {
+		t.Fatalf("resolving changed graph with resolver: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"C", "D", "A", "B"}) {
+		t.Fatalf("changed graph resolved with resolver incorrectly: %v", res)
+	}
+
+	dg.Add("C", "B")
+
+	_, err = r.Resolve()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved graph with circular dependency: %v", err)
+	}
+}
+
+// TestResolverAllocs tests that a resolver only allocates the resulting slice.
+func TestResolverAllocs(t *testing.T) {
+	dg := GenerateRandomDAG(200, 0.05, 1, func(i int) int {
+		return i
+	})
+
+	r := dg.NewResolver()
+	_, err := r.Resolve()
+	if err != nil {
+		t.Fatalf("resolving generated graph: %v", err)
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		for _, err := range r.ResolveIter() {
+			if err != nil {
+				t.Fatalf("resolving generated graph: %v", err)
+			}
+		}
+	})
+
+	if allocs > 0 {
+		t.Fatalf("resolver allocates %v times per resolution", allocs)
+	}
+}
+
+// TestResolverBlockers tests that the blockers reflect the state of the resolution in progress.
+func TestResolverBlockers(t *testing.T) {
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		return nil
+	}))
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C", "A", "B", "X")
+
+	r := dg.NewResolver()
+
+	if blockers := r.Blockers("C"); !slices.Equal(blockers, []string{"A", "B"}) {
+		t.Fatalf("blockers before resolution listed incorrectly: %v", blockers)
+	}
+
+	expected := map[string][]string{
+		"A": {"B"},
+		"B": {},
+		"C": {},
+	}
+
+	for el, err := range r.ResolveIter() {
+		if err != nil {
+			t.Fatalf("resolving graph with resolver: %v", err)
+		}
+
+		if blockers := r.Blockers("C"); !slices.Equal(blockers, expected[el]) {
+			t.Fatalf("blockers after resolving %q listed incorrectly: %v", el, blockers)
+		}
+	}
+
+	if blockers := r.Blockers("X"); blockers != nil {
+		t.Fatalf("blockers listed for an unknown node: %v", blockers)
+	}
+}
+
+// TestResolverUnlockedBy tests that the nodes waiting only for a given node are reported.
+func TestResolverUnlockedBy(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B")
+	dg.Add("C", "A")
+	dg.Add("D", "A", "B")
+	dg.Add("E", "B")
+
+	r := dg.NewResolver()
+
+	if unlocked := r.UnlockedBy("A"); !slices.Equal(unlocked, []string{"C"}) {
+		t.Fatalf("nodes unlocked before resolution listed incorrectly: %v", unlocked)
+	}
+
+	for el, err := range r.ResolveIter() {
+		if err != nil {
+			t.Fatalf("resolving graph with resolver: %v", err)
+		}
+
+		if el == "A" {
+			break
+		}
+	}
+
+	if unlocked := r.UnlockedBy("B"); !slices.Equal(unlocked, []string{"D", "E"}) {
+		t.Fatalf("nodes unlocked during resolution listed incorrectly: %v", unlocked)
+	}
+
+	if unlocked := r.UnlockedBy("A"); unlocked != nil {
+		t.Fatalf("nodes unlocked by a resolved node: %v", unlocked)
+	}
+
+	if unlocked := r.UnlockedBy("X"); unlocked != nil {
+		t.Fatalf("nodes unlocked by an unknown node: %v", unlocked)
+	}
+
+	// A resolved node, which has got a new dependency since, is not waiting for anything.
+	dg.Add("A", "F")
+	dg.Add("F")
+
+	if unlocked := r.UnlockedBy("F"); len(unlocked) != 0 {
+		t.Fatalf("resolved nodes listed as unlocked: %v", unlocked)
+	}
+}
@@ -0,0 +1,155 @@
+package depgraph
+
+import (
+	"slices"
+	"time"
+)
+
+// ScheduledNode is an element of a simulated schedule.
+type ScheduledNode[T comparable] struct {
+	// Node is the scheduled element.
+	Node T
+
+	// Worker is the index of the worker, which processes the element.
+	Worker int
+
+	// Start is the moment the element starts being processed, relative to the start of the schedule.
+	Start time.Duration
+
+	// End is the moment the element is done, relative to the start of the schedule.
+	End time.Duration
+}
+
+// SimulateSchedule simulates processing the graph on a given number of parallel workers,
+// where every element takes the time returned by cost, and may only start once all of its dependencies are done.
+// It uses the list scheduling heuristic: whenever a worker is idle, it picks the free element,
+// which goes first in the edge list order; the lowest-numbered idle worker is always picked first.
+// Elements declared exclusive with Exclusive are never run concurrently.
+// It returns the schedule, ordered by the start time, and the makespan, which is the moment the last element is done.
+// A number of workers less than one is treated as one.
+// Unknown dependencies are treated as satisfied, and elements with circular dependencies are never scheduled.
+func (dg *DependencyGraph[T]) SimulateSchedule(workers int, cost func(T) time.Duration) ([]ScheduledNode[T], time.Duration) {
+	workers = max(workers, 1)
+	refcounts, dependents := dg.adjacency()
+
+	ready := []int{}
+	for i := range dg.edges {
+		if refcounts[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	idle := make([]int, workers)
+	for i := range idle {
+		idle[i] = i
+	}
+
+	// Running elements are referred to by their position in the schedule.
+	res := make([]ScheduledNode[T], 0, len(dg.edges))
+	running := []int{}
+	indices := make([]int, 0, len(dg.edges))
+	now := time.Duration(0)
+	used := make([]bool, dg.exclusiveSets)
+
+	for len(ready) > 0 || len(running) > 0 {
+		// Start the free elements, skipping the ones that conflict with a running exclusive element.
+		waiting := ready[:0]
+
+		for _, idx := range ready {
+			node := dg.edges[idx].name
+
+			if len(idle) == 0 || !dg.claimExclusive(node, used) {
+				waiting = append(waiting, idx)
+				continue
+			}
+
+			worker := idle[0]
+			idle = idle[1:]
+
+			indices = append(indices, idx)
+			running = append(running, len(res))
+			res = append(res, ScheduledNode[T]{
+				Node:   node,
+				Worker: worker,
+				Start:  now,
+				End:    now + cost(node),
+			})
+		}
+
+		ready = waiting
+
+		// Advance to the moment the earliest running element is done,
+		// and complete all the elements that are done by then.
+		now = res[running[0]].End
+		for _, pos := range running[1:] {
+			now = min(now, res[pos].End)
+		}
+
+		running = slices.DeleteFunc(running, func(pos int) bool {
+			if res[pos].End != now {
+				return false
+			}
+
+			idle = append(idle, res[pos].Worker)
+			dg.releaseExclusive(res[pos].Node, used)
+
+			for _, d := range dependents[indices[pos]] {
+				refcounts[d]--
+
+				if refcounts[d] == 0 {
+					ready = append(ready, d)
+				}
+			}
+
+			return true
+		})
+
+		slices.Sort(idle)
+		slices.Sort(ready)
+	}
+
+	return res, now
+}
+
+// ResolveWithFailures simulates a run of the graph, where some elements fail, as reported by fails:
+// the elements are processed in dependency order, and every element, which depends on a failed element,
+// either directly or transitively, is skipped instead of being run.
+// It returns the elements, which have been run, including the failed ones, and the skipped elements,
+// both in dependency order; fails is never called for skipped elements.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (dg *DependencyGraph[T]) ResolveWithFailures(fails func(T) bool) ([]T, []T, error) {
+	resolved := []T{}
+	skipped := []T{}
+
+	// Both failed and skipped elements make their dependents skipped.
+	broken := map[T]struct{}{}
+
+	for el, err := range dg.ResolveIter() {
+		if err != nil {
+			return nil, nil, err
+		}
+
+		skip := false
+		for _, dep := range dg.edgeMap[el].depOrder {
+			if _, ok := broken[dep]; ok {
+				skip = true
+				break
+			}
+		}
+
+		if skip {
+			broken[el] = struct{}{}
+			skipped = append(skipped, el)
+
+			continue
+		}
+
+		resolved = append(resolved, el)
+
+		if fails(el) {
+			broken[el] = struct{}{}
+		}
+	}
+
+	return resolved, skipped, nil
+}
@@ -0,0 +1,84 @@
+package depgraph
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestSimulateSchedule tests the simulated schedule on one and two workers.
+func TestSimulateSchedule(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("fetch")
+	dg.Add("lint")
+	dg.Add("compile", "fetch")
+	dg.Add("test", "compile")
+	dg.Add("docs")
+	dg.Add("release", "test", "lint", "docs")
+
+	costs := map[string]time.Duration{
+		"fetch":   2 * time.Second,
+		"lint":    time.Second,
+		"compile": 3 * time.Second,
+		"test":    2 * time.Second,
+		"docs":    4 * time.Second,
+		"release": time.Second,
+	}
+
+	cost := func(node string) time.Duration {
+		return costs[node]
+	}
+
+	schedule, makespan := dg.SimulateSchedule(1, cost)
+	if makespan != 13*time.Second || len(schedule) != 6 {
+		t.Fatalf("single worker schedule computed incorrectly: %v, %v", schedule, makespan)
+	}
+
+	schedule, makespan = dg.SimulateSchedule(2, cost)
+
+	expected := []ScheduledNode[string]{
+		{Node: "fetch", Worker: 0, Start: 0, End: 2 * time.Second},
+		{Node: "lint", Worker: 1, Start: 0, End: time.Second},
+		{Node: "docs", Worker: 1, Start: time.Second, End: 5 * time.Second},
+		{Node: "compile", Worker: 0, Start: 2 * time.Second, End: 5 * time.Second},
+		{Node: "test", Worker: 0, Start: 5 * time.Second, End: 7 * time.Second},
+		{Node: "release", Worker: 0, Start: 7 * time.Second, End: 8 * time.Second},
+	}
+
+	if !slices.Equal(schedule, expected) || makespan != 8*time.Second {
+		t.Fatalf("two worker schedule computed incorrectly: %v, %v", schedule, makespan)
+	}
+}
+
+// TestResolveWithFailures tests that the dependents of failed elements get skipped.
+func TestResolveWithFailures(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("fetch")
+	dg.Add("compile", "fetch")
+	dg.Add("test", "compile")
+	dg.Add("lint", "fetch")
+	dg.Add("docs")
+	dg.Add("release", "test", "docs")
+
+	called := []string{}
+
+	resolved, skipped, err := dg.ResolveWithFailures(func(node string) bool {
+		called = append(called, node)
+		return node == "compile"
+	})
+	if err != nil {
+		t.Fatalf("resolving graph with failures: %v", err)
+	}
+
+	if !slices.Equal(resolved, []string{"fetch", "docs", "lint", "compile"}) {
+		t.Fatalf("elements run incorrectly: %v", resolved)
+	}
+
+	if !slices.Equal(skipped, []string{"test", "release"}) {
+		t.Fatalf("elements skipped incorrectly: %v", skipped)
+	}
+
+	if !slices.Equal(called, resolved) {
+		t.Fatalf("failures checked for unexpected elements: %v", called)
+	}
+}
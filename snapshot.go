@@ -0,0 +1,64 @@
+package depgraph
+
+// Snapshot represents a point-in-time state of a dependency graph's nodes and edges.
+// It is created by DependencyGraph.Snapshot and may be reinstated with DependencyGraph.Restore.
+// The zero value represents an empty graph.
+type Snapshot[T comparable] struct {
+	edges   []*depEdge[T]
+	edgeMap map[T]*depEdge[T]
+	aliases map[T]T
+}
+
+// Snapshot captures the current state of the graph.
+// Taking a snapshot is cheap: the graph's state is shared with the snapshot
+// and only gets copied once the graph is mutated afterwards.
+func (dg *DependencyGraph[T]) Snapshot() Snapshot[T] {
+	// A frozen graph never mutates its edges, so there is no need to mark them as shared.
+	if !dg.frozen {
+		dg.shared = true
+	}
+
+	return Snapshot[T]{
+		edges:   dg.edges,
+		edgeMap: dg.edgeMap,
+		aliases: dg.aliases,
+	}
+}
+
+// Restore reinstates the graph's state from a snapshot, discarding all changes
+// made since the snapshot was taken.
+// The same snapshot may be restored any number of times.
+func (dg *DependencyGraph[T]) Restore(snap Snapshot[T]) {
+	dg.checkFrozen()
+
+	dg.validated = false
+	dg.reach = nil
+	dg.edges = snap.edges
+	dg.edgeMap = snap.edgeMap
+	dg.aliases = snap.aliases
+	dg.shared = true
+}
+
+// own makes sure that the graph exclusively owns its edges,
+// copying them if they are shared with a snapshot.
+func (dg *DependencyGraph[T]) own() {
+	if !dg.shared {
+		return
+	}
+
+	edges := make([]*depEdge[T], 0, len(dg.edges))
+	edgeMap := make(map[T]*depEdge[T], len(dg.edges))
+
+	for _, edge := range dg.edges {
+		clone := cloneEdge(edge, func(_, _ T) bool {
+			return true
+		})
+
+		edges = append(edges, clone)
+		edgeMap[clone.name] = clone
+	}
+
+	dg.edges = edges
+	dg.edgeMap = edgeMap
+	dg.shared = false
+}
@@ -0,0 +1,78 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestSnapshotRestore tests that restoring a snapshot rolls back all mutations,
+// including the ones made to already existing nodes.
+func TestSnapshotRestore(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C")
+
+	snap := dg.Snapshot()
+
+	dg.Add("A", "C")
+	dg.Add("C", "A")
+	dg.Add("D")
+
+	_, err := dg.Resolve()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved graph with circular dependency: %v", err)
+	}
+
+	dg.Restore(snap)
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving restored graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "C", "B"}) {
+		t.Fatalf("restored graph resolved incorrectly: %v", res)
+	}
+
+	// The snapshot must remain intact after being restored and mutated again.
+	dg.Add("E", "B")
+	dg.Restore(snap)
+
+	res, err = dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving twice restored graph: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "C", "B"}) {
+		t.Fatalf("twice restored graph resolved incorrectly: %v", res)
+	}
+}
+
+// TestSnapshotZero tests that restoring a zero snapshot yields an empty graph.
+func TestSnapshotZero(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Restore(Snapshot[string]{})
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving zero-restored graph: %v", err)
+	}
+
+	if len(res) != 0 {
+		t.Fatalf("zero-restored graph is not empty: %v", res)
+	}
+
+	dg.Add("B")
+
+	res, err = dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving zero-restored graph after adding: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"B"}) {
+		t.Fatalf("zero-restored graph resolved incorrectly: %v", res)
+	}
+}
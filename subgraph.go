@@ -0,0 +1,204 @@
+package depgraph
+
+import "fmt"
+
+// derive creates a new graph with the same options, which contains only the nodes accepted by keepNode
+// and only the dependencies accepted by keepDep, preserving the edge list order.
+// The capabilities are left out, since they are already wired as regular dependencies,
+// while their providers may not have been kept.
+// The original graph is not modified.
+func (dg *DependencyGraph[T]) derive(keepNode func(name T) bool, keepDep func(name, dep T) bool) *DependencyGraph[T] {
+	sub := *dg
+	sub.edges = make([]*depEdge[T], 0, len(dg.edges))
+	sub.edgeMap = make(map[T]*depEdge[T], len(dg.edges))
+	sub.shared = false
+	sub.frozen = false
+	sub.validated = false
+	sub.reach = nil
+
+	// Leaving nodes out may legitimately split a connected graph, so only the original one is required to be connected.
+	sub.requireConnected = false
+
+	for _, edge := range dg.edges {
+		if !keepNode(edge.name) {
+			continue
+		}
+
+		clone := cloneEdge(edge, keepDep)
+		clone.provides, clone.requires = nil, nil
+		sub.edges = append(sub.edges, clone)
+		sub.edgeMap[clone.name] = clone
+	}
+
+	return &sub
+}
+
+// ResolveExcluding resolves the graph as if the excluded nodes did not exist:
+// they are omitted from the output, and the dependencies on them are dropped,
+// so that nodes which depended only on excluded nodes become free.
+// If the graph has been created with WithStrictExclusions, depending on an excluded node
+// is an error instead.
+// The graph itself is not modified.
+func (dg *DependencyGraph[T]) ResolveExcluding(exclude ...T) ([]T, error) {
+	excluded := make(map[T]struct{}, len(exclude))
+	for _, el := range exclude {
+		excluded[el] = struct{}{}
+	}
+
+	isExcluded := func(name T) bool {
+		_, ok := excluded[name]
+		return ok
+	}
+
+	if dg.strictExclusions {
+		for _, edge := range dg.edges {
+			if isExcluded(edge.name) {
+				continue
+			}
+
+			for _, dep := range edge.depOrder {
+				if isExcluded(dep) {
+					return nil, fmt.Errorf("node \"%v\" depends on \"%v\": %w", edge.name, dep, ErrExcludedDependency)
+				}
+			}
+		}
+	}
+
+	sub := dg.derive(func(name T) bool {
+		return !isExcluded(name)
+	}, func(_, dep T) bool {
+		return !isExcluded(dep)
+	})
+
+	return sub.Resolve()
+}
+
+// ResolveFrom resumes the resolution from a partial ordering of nodes that are already done,
+// such as the part of a previous resolution that had been completed before an interruption.
+// The prefix must be a valid beginning of a dependency order: every node in it must be known,
+// must occur only once, and must follow all of its dependencies.
+// The prefix nodes are omitted from the output, and the dependencies on them are treated as satisfied.
+// The graph itself is not modified.
+func (dg *DependencyGraph[T]) ResolveFrom(prefix []T) ([]T, error) {
+	// The validation may add nodes supplied by the node provider, or normalize the dependencies,
+	// so it is run on a copy, which shares the edges with the graph until it gets modified.
+	full := *dg
+	full.shared = true
+	full.reach = nil
+
+	err := full.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("validating dependency graph: %w", err)
+	}
+
+	done := make(map[T]struct{}, len(prefix))
+
+	isDone := func(name T) bool {
+		_, ok := done[name]
+		return ok
+	}
+
+	for i, el := range prefix {
+		edge, ok := full.edgeMap[el]
+		if !ok {
+			return nil, fmt.Errorf("prefix element %d \"%v\": %w", i, el, ErrUnknownNode)
+		}
+
+		if isDone(el) {
+			return nil, fmt.Errorf("prefix element %d \"%v\" is repeated: %w", i, el, ErrInvalidPrefix)
+		}
+
+		for _, dep := range edge.depOrder {
+			if _, known := full.edgeMap[dep]; known && !isDone(dep) {
+				return nil, fmt.Errorf("prefix element %d \"%v\" precedes its dependency \"%v\": %w", i, el, dep, ErrInvalidPrefix)
+			}
+		}
+
+		done[el] = struct{}{}
+	}
+
+	sub := full.derive(func(name T) bool {
+		return !isDone(name)
+	}, func(_, dep T) bool {
+		return !isDone(dep)
+	})
+
+	return sub.Resolve()
+}
+
+// ResolveAgainst resolves the graph against an inventory of nodes, which are already present,
+// as reported by installed: such nodes are omitted from the output, and the dependencies on them
+// are treated as satisfied, even if they are not present in the graph.
+// The installed function is queried lazily, at most once for every node.
+// The graph itself is not modified.
+func (dg *DependencyGraph[T]) ResolveAgainst(installed func(T) bool) ([]T, error) {
+	known := map[T]bool{}
+
+	isInstalled := func(name T) bool {
+		res, ok := known[name]
+		if !ok {
+			res = installed(name)
+			known[name] = res
+		}
+
+		return res
+	}
+
+	sub := dg.derive(func(name T) bool {
+		return !isInstalled(name)
+	}, func(_, dep T) bool {
+		return !isInstalled(dep)
+	})
+
+	return sub.Resolve()
+}
+
+// RebuildOrder returns the nodes, which transitively depend on any of the changed nodes,
+// and therefore have to be rebuilt, in dependency order.
+// The changed nodes themselves are only included if they depend on another changed node.
+// Dependencies on the nodes, which are not affected by the change, are treated as satisfied.
+// An error is returned if any of the changed nodes is not present in the graph,
+// otherwise the errors are the same as the ones of Resolve.
+func (dg *DependencyGraph[T]) RebuildOrder(changed ...T) ([]T, error) {
+	_, dependents := dg.adjacency()
+
+	positions := make(map[T]int, len(dg.edges))
+	for i, edge := range dg.edges {
+		positions[edge.name] = i
+	}
+
+	affected := map[T]struct{}{}
+	stack := make([]int, 0, len(changed))
+
+	for _, name := range changed {
+		i, ok := positions[name]
+		if !ok {
+			return nil, fmt.Errorf("looking up changed node \"%v\": %w", name, ErrUnknownNode)
+		}
+
+		stack = append(stack, i)
+	}
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, d := range dependents[cur] {
+			if _, ok := affected[dg.edges[d].name]; !ok {
+				affected[dg.edges[d].name] = struct{}{}
+				stack = append(stack, d)
+			}
+		}
+	}
+
+	isAffected := func(name T) bool {
+		_, ok := affected[name]
+		return ok
+	}
+
+	sub := dg.derive(isAffected, func(_, dep T) bool {
+		return isAffected(dep)
+	})
+
+	return sub.Resolve()
+}
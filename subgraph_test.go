@@ -0,0 +1,268 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestResolveExcluding tests that excluded nodes are omitted from the resolution,
+// and that dependencies on them get dropped.
+func TestResolveExcluding(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "C")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "B")
+	dg.Add("E", "D")
+
+	res, err := dg.ResolveExcluding("C", "D")
+	if err != nil {
+		t.Fatalf("resolving graph with exclusions: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "E", "B"}) {
+		t.Fatalf("graph with exclusions resolved incorrectly: %v", res)
+	}
+
+	res, err = dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph after exclusions: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"C", "A", "B", "D", "E"}) {
+		t.Fatalf("graph resolved incorrectly after exclusions: %v", res)
+	}
+}
+
+// TestResolveExcludingStrict tests that depending on an excluded node is an error in the strict mode.
+func TestResolveExcludingStrict(t *testing.T) {
+	dg := NewDependencyGraph(WithStrictExclusions[string]())
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "B")
+
+	res, err := dg.ResolveExcluding("C")
+	if err != nil {
+		t.Fatalf("resolving strict graph with exclusions: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"A", "B"}) {
+		t.Fatalf("strict graph with exclusions resolved incorrectly: %v", res)
+	}
+
+	_, err = dg.ResolveExcluding("A")
+	if !errors.Is(err, ErrExcludedDependency) {
+		t.Fatalf("resolved strict graph with excluded dependency: %v", err)
+	}
+}
+
+// TestResolveFrom tests resuming the resolution from a completed prefix,
+// as well as the rejection of invalid prefixes.
+func TestResolveFrom(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "C")
+	dg.Add("B", "A")
+	dg.Add("C")
+	dg.Add("D", "B")
+	dg.Add("E", "C")
+
+	full, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph: %v", err)
+	}
+
+	for i := range len(full) + 1 {
+		res, err := dg.ResolveFrom(full[:i])
+		if err != nil {
+			t.Fatalf("resolving graph from prefix %v: %v", full[:i], err)
+		}
+
+		if len(res) != len(full)-i {
+			t.Fatalf("graph resolved from prefix %v has %d elements: %v", full[:i], len(res), res)
+		}
+
+		if !isDependencyOrder(dg, append(slices.Clone(full[:i]), res...)) {
+			t.Fatalf("graph resolved from prefix %v incorrectly: %v", full[:i], res)
+		}
+	}
+
+	_, err = dg.ResolveFrom([]string{"C", "B"})
+	if !errors.Is(err, ErrInvalidPrefix) {
+		t.Fatalf("prefix violating dependencies accepted: %v", err)
+	}
+
+	_, err = dg.ResolveFrom([]string{"C", "C"})
+	if !errors.Is(err, ErrInvalidPrefix) {
+		t.Fatalf("prefix with a repeated element accepted: %v", err)
+	}
+
+	_, err = dg.ResolveFrom([]string{"C", "X"})
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("prefix with an unknown element accepted: %v", err)
+	}
+}
+
+// TestResolveFromProvider tests that resuming the resolution does not modify the graph,
+// even if the validation has to supply some of the dependencies.
+func TestResolveFromProvider(t *testing.T) {
+	dg := NewDependencyGraph(WithNodeProvider(func(dep string) ([]string, bool) {
+		return nil, dep == "P"
+	}))
+	dg.Add("A")
+	dg.Add("B", "A", "P")
+
+	res, err := dg.ResolveFrom([]string{"A"})
+	if err != nil {
+		t.Fatalf("resolving graph with provided dependency from prefix: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"P", "B"}) {
+		t.Fatalf("graph with provided dependency resolved from prefix incorrectly: %v", res)
+	}
+
+	if _, ok := dg.edgeMap["P"]; ok || len(dg.edges) != 2 {
+		t.Fatalf("graph has been modified by resuming the resolution")
+	}
+}
+
+// isDependencyOrder reports whether the order contains each of the graph's nodes exactly once,
+// and whether every node follows all of its known dependencies.
+func isDependencyOrder(dg *DependencyGraph[string], order []string) bool {
+	if len(order) != len(dg.edges) {
+		return false
+	}
+
+	pos := make(map[string]int, len(order))
+	for i, el := range order {
+		if _, ok := pos[el]; ok {
+			return false
+		}
+
+		pos[el] = i
+	}
+
+	for _, edge := range dg.edges {
+		i, ok := pos[edge.name]
+		if !ok {
+			return false
+		}
+
+		for _, dep := range edge.depOrder {
+			if j, known := pos[dep]; known && j > i {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// TestResolveAgainst tests that installed nodes are omitted, and that dependencies on them are satisfied.
+func TestResolveAgainst(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "libssl", "libc")
+	dg.Add("libssl", "libc", "kernel")
+	dg.Add("libc")
+	dg.Add("tool", "libc")
+
+	queries := map[string]int{}
+
+	res, err := dg.ResolveAgainst(func(node string) bool {
+		queries[node]++
+		return node == "libc" || node == "kernel"
+	})
+	if err != nil {
+		t.Fatalf("resolving graph against inventory: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"libssl", "tool", "app"}) {
+		t.Fatalf("graph resolved against inventory incorrectly: %v", res)
+	}
+
+	for node, n := range queries {
+		if n != 1 {
+			t.Fatalf("inventory queried for %q %d times", node, n)
+		}
+	}
+
+	_, err = dg.ResolveAgainst(func(node string) bool {
+		return node == "libc"
+	})
+	if !errors.Is(err, ErrUnknownDependency) {
+		t.Fatalf("resolved graph with a missing dependency: %v", err)
+	}
+}
+
+// TestRebuildOrder tests that the transitive dependents of changed nodes are returned in dependency order.
+func TestRebuildOrder(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "lib", "util")
+	dg.Add("lib", "util.go")
+	dg.Add("util", "util.go", "log")
+	dg.Add("util.go")
+	dg.Add("test", "lib")
+	dg.Add("log")
+
+	res, err := dg.RebuildOrder("util.go")
+	if err != nil {
+		t.Fatalf("computing rebuild order: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"lib", "util", "test", "app"}) {
+		t.Fatalf("rebuild order computed incorrectly: %v", res)
+	}
+
+	res, err = dg.RebuildOrder("log", "util")
+	if err != nil {
+		t.Fatalf("computing rebuild order: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"util", "app"}) {
+		t.Fatalf("rebuild order of several changes computed incorrectly: %v", res)
+	}
+
+	_, err = dg.RebuildOrder("X")
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("computed rebuild order of an unknown node: %v", err)
+	}
+}
+
+// TestSubgraphsConnected tests that resolving a part of a graph, which is required to be connected,
+// does not require the part itself to be connected.
+func TestSubgraphsConnected(t *testing.T) {
+	dg := NewDependencyGraph(WithRequireConnected[string]())
+	dg.Add("A")
+	dg.Add("B", "A")
+	dg.Add("C", "A")
+
+	tbl := []struct {
+		name    string
+		resolve func() ([]string, error)
+	}{
+		{name: "RebuildOrder", resolve: func() ([]string, error) { return dg.RebuildOrder("A") }},
+		{name: "ResolveFrom", resolve: func() ([]string, error) { return dg.ResolveFrom([]string{"A"}) }},
+		{name: "ResolveAgainst", resolve: func() ([]string, error) {
+			return dg.ResolveAgainst(func(name string) bool { return name == "A" })
+		}},
+		{name: "ResolveExcluding", resolve: func() ([]string, error) { return dg.ResolveExcluding("A") }},
+	}
+
+	for _, test := range tbl {
+		res, err := test.resolve()
+		if err != nil {
+			t.Fatalf("%s: resolving disconnected part of connected graph: %v", test.name, err)
+		}
+
+		if !slices.Equal(res, []string{"B", "C"}) {
+			t.Fatalf("%s: disconnected part of connected graph resolved incorrectly: %v", test.name, res)
+		}
+	}
+
+	dg.Add("D")
+
+	_, err := dg.ResolveFrom([]string{"A"})
+	if !errors.Is(err, ErrDisconnectedGraph) {
+		t.Fatalf("resolved disconnected graph from prefix: %v", err)
+	}
+}
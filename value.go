@@ -0,0 +1,85 @@
+package depgraph
+
+// ValueGraph is a dependency graph, which additionally stores an arbitrary value for its elements,
+// turning it into a container rather than just an ordering oracle.
+// All methods of DependencyGraph are available on it as well.
+type ValueGraph[T comparable, V any] struct {
+	*DependencyGraph[T]
+
+	values map[T]V
+}
+
+// Entry is a graph element paired with its value.
+type Entry[T comparable, V any] struct {
+	Node  T
+	Value V
+}
+
+// NewValueGraph creates a new stable dependency graph, which stores values of type V for its elements.
+// Its behavior may be tuned by passing one or more options.
+func NewValueGraph[T comparable, V any](opts ...Option[T]) *ValueGraph[T, V] {
+	return &ValueGraph[T, V]{
+		DependencyGraph: NewDependencyGraph(opts...),
+		values:          map[T]V{},
+	}
+}
+
+// AddWithValue adds an element, just like Add does, and sets its value.
+// If the element already has a value, it gets replaced.
+func (vg *ValueGraph[T, V]) AddWithValue(name T, value V, deps ...T) {
+	vg.Add(name, deps...)
+	vg.values[vg.resolveAlias(name)] = value
+}
+
+// Value returns the value of an element, which may also be referred to by any of its aliases,
+// and whether it has been set.
+func (vg *ValueGraph[T, V]) Value(name T) (V, bool) {
+	value, ok := vg.values[vg.resolveAlias(name)]
+	return value, ok
+}
+
+// Alias works like DependencyGraph.Alias, and additionally moves the value of alias, if any,
+// to canonical, unless canonical already has a value of its own.
+func (vg *ValueGraph[T, V]) Alias(canonical, alias T) error {
+	err := vg.DependencyGraph.Alias(canonical, alias)
+	if err != nil {
+		return err
+	}
+
+	if value, ok := vg.values[alias]; ok {
+		delete(vg.values, alias)
+
+		canonical = vg.resolveAlias(canonical)
+		if _, ok := vg.values[canonical]; !ok {
+			vg.values[canonical] = value
+		}
+	}
+
+	return nil
+}
+
+// Clear removes all elements and their values from the graph, while retaining its allocated capacity.
+func (vg *ValueGraph[T, V]) Clear() {
+	vg.DependencyGraph.Clear()
+	clear(vg.values)
+}
+
+// ResolveEntries returns the graph's elements in dependency order, paired with their values.
+// Elements without a value are paired with the zero value.
+// If a circular dependency is detected, or if the graph is invalid, it returns a *ResolveError.
+func (vg *ValueGraph[T, V]) ResolveEntries() ([]Entry[T, V], error) {
+	res := make([]Entry[T, V], 0, len(vg.edges))
+
+	for el, err := range vg.ResolveIter() {
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, Entry[T, V]{
+			Node:  el,
+			Value: vg.values[el],
+		})
+	}
+
+	return res, nil
+}
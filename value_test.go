@@ -0,0 +1,97 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestValueGraph tests that values get stored and yielded along with the resolution order.
+func TestValueGraph(t *testing.T) {
+	vg := NewValueGraph[string, int]()
+	vg.AddWithValue("B", 2, "A")
+	vg.AddWithValue("A", 1)
+	vg.Add("C", "B")
+	vg.AddWithValue("A", 10)
+
+	if value, ok := vg.Value("A"); !ok || value != 10 {
+		t.Fatalf("value retrieved incorrectly: %v, %v", value, ok)
+	}
+
+	if value, ok := vg.Value("C"); ok || value != 0 {
+		t.Fatalf("unset value retrieved: %v", value)
+	}
+
+	res, err := vg.ResolveEntries()
+	if err != nil {
+		t.Fatalf("resolving value graph: %v", err)
+	}
+
+	if !slices.Equal(res, []Entry[string, int]{{"A", 10}, {"B", 2}, {"C", 0}}) {
+		t.Fatalf("value graph resolved incorrectly: %v", res)
+	}
+
+	vg.Add("A", "C")
+
+	_, err = vg.ResolveEntries()
+	if !errors.Is(err, ErrCircularDependency) {
+		t.Fatalf("resolved value graph with circular dependency: %v", err)
+	}
+
+	vg.Clear()
+
+	if _, ok := vg.Value("A"); ok {
+		t.Fatalf("value retrieved after clearing")
+	}
+}
+
+// TestValueGraphAlias tests that values are stored under the canonical names,
+// and that aliasing moves an existing value.
+func TestValueGraphAlias(t *testing.T) {
+	vg := NewValueGraph[string, int]()
+	vg.AddWithValue("B", 7)
+	vg.Add("A")
+
+	err := vg.Alias("A", "B")
+	if err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+
+	if value, ok := vg.Value("A"); !ok || value != 7 {
+		t.Fatalf("value not moved to the canonical name: %v, %v", value, ok)
+	}
+
+	vg.AddWithValue("B", 5)
+
+	for _, name := range []string{"A", "B"} {
+		if value, ok := vg.Value(name); !ok || value != 5 {
+			t.Fatalf("value of %v set incorrectly: %v, %v", name, value, ok)
+		}
+	}
+
+	res, err := vg.ResolveEntries()
+	if err != nil {
+		t.Fatalf("resolving aliased value graph: %v", err)
+	}
+
+	if !slices.Equal(res, []Entry[string, int]{{Node: "A", Value: 5}}) {
+		t.Fatalf("aliased value graph resolved incorrectly: %v", res)
+	}
+
+	err = vg.Alias("A", "A")
+	if !errors.Is(err, ErrAliasCycle) {
+		t.Fatalf("aliased element to itself: %v", err)
+	}
+
+	// The value of the canonical element takes precedence.
+	vg.AddWithValue("C", 1)
+
+	err = vg.Alias("A", "C")
+	if err != nil {
+		t.Fatalf("adding alias: %v", err)
+	}
+
+	if value, _ := vg.Value("C"); value != 5 {
+		t.Fatalf("value of the canonical element replaced by the alias: %v", value)
+	}
+}
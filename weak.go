@@ -0,0 +1,117 @@
+package depgraph
+
+import "fmt"
+
+// AddWeak adds a weak dependency of an element on dep, adding the element itself if needed:
+// a weak dependency only affects the ordering when both of its endpoints get resolved,
+// but never requires dep to be present.
+// A weak dependency on a node, which is not present in the graph, is ignored by the validation,
+// and ResolveTargets does not pull dep in unless some target needs it through regular dependencies.
+// In all other respects, such as cycle detection, weak dependencies are treated as regular ones.
+// Adding the same dependency with Add, either before or after, makes it regular.
+func (dg *DependencyGraph[T]) AddWeak(name, dep T) {
+	if len(dg.aliases) > 0 {
+		dep = dg.resolveAlias(dep)
+	}
+
+	dg.Add(name)
+
+	edge := dg.edgeMap[dg.resolveAlias(name)]
+	if _, ok := edge.deps[dep]; ok {
+		return
+	}
+
+	edge.deps[dep] = struct{}{}
+	edge.depOrder = append(edge.depOrder, dep)
+
+	if dg.reach != nil {
+		dg.reach.add(edge.name, dep)
+	}
+
+	if edge.weak == nil {
+		edge.weak = depList[T]{}
+	}
+
+	edge.weak[dep] = struct{}{}
+}
+
+// addDeps works like Add, but adds the dependencies listed in weak with AddWeak,
+// so that the weak dependencies of another graph's element stay weak once copied.
+func (dg *DependencyGraph[T]) addDeps(name T, deps []T, weak depList[T]) {
+	if len(weak) == 0 {
+		dg.Add(name, deps...)
+		return
+	}
+
+	dg.Add(name)
+
+	for _, dep := range deps {
+		if _, ok := weak[dep]; ok {
+			dg.AddWeak(name, dep)
+		} else {
+			dg.Add(name, dep)
+		}
+	}
+}
+
+// ResolveTargets resolves only the given targets, along with their transitive dependencies,
+// as if the other nodes did not exist.
+// Only regular dependencies pull nodes in; weak dependencies, as added by AddWeak,
+// are honored only between the nodes, which get resolved anyway.
+// An error is returned if any of the targets is not present in the graph,
+// otherwise the errors are the same as the ones of Resolve.
+// The graph itself is not modified.
+func (dg *DependencyGraph[T]) ResolveTargets(targets ...T) ([]T, error) {
+	needed := make(map[T]struct{}, len(targets))
+	stack := make([]T, 0, len(targets))
+
+	for _, target := range targets {
+		target = dg.resolveAlias(target)
+
+		if _, ok := dg.edgeMap[target]; !ok {
+			return nil, fmt.Errorf("looking up target \"%v\": %w", target, ErrUnknownNode)
+		}
+
+		if _, ok := needed[target]; !ok {
+			needed[target] = struct{}{}
+			stack = append(stack, target)
+		}
+	}
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		edge, ok := dg.edgeMap[cur]
+		if !ok {
+			continue
+		}
+
+		for _, dep := range edge.depOrder {
+			if _, weak := edge.weak[dep]; weak {
+				continue
+			}
+
+			if _, ok := needed[dep]; !ok {
+				needed[dep] = struct{}{}
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	sub := dg.derive(func(name T) bool {
+		_, ok := needed[name]
+		return ok
+	}, func(name, dep T) bool {
+		_, ok := needed[dep]
+		return ok || !dg.isWeak(name, dep)
+	})
+
+	return sub.Resolve()
+}
+
+// isWeak reports whether an element's dependency has only been added with AddWeak.
+func (dg *DependencyGraph[T]) isWeak(name, dep T) bool {
+	_, ok := dg.edgeMap[name].weak[dep]
+	return ok
+}
@@ -0,0 +1,105 @@
+package depgraph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestWeakDependencies tests that weak dependencies affect the ordering, but never pull their nodes in.
+func TestWeakDependencies(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "lib")
+	dg.Add("docs")
+	dg.Add("lib")
+	dg.AddWeak("app", "docs")
+	dg.AddWeak("lib", "codegen")
+
+	res, err := dg.Resolve()
+	if err != nil {
+		t.Fatalf("resolving graph with weak dependencies: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"docs", "lib", "app"}) {
+		t.Fatalf("graph with weak dependencies resolved incorrectly: %v", res)
+	}
+
+	res, err = dg.ResolveTargets("app")
+	if err != nil {
+		t.Fatalf("resolving targets with weak dependencies: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"lib", "app"}) {
+		t.Fatalf("weak dependency pulled into targets: %v", res)
+	}
+
+	res, err = dg.ResolveTargets("app", "docs")
+	if err != nil {
+		t.Fatalf("resolving targets with weak dependencies: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"docs", "lib", "app"}) {
+		t.Fatalf("weak dependency between targets ignored: %v", res)
+	}
+
+	dg.Add("app", "docs")
+
+	if dg.isWeak("app", "docs") {
+		t.Fatalf("regular dependency has not superseded the weak one")
+	}
+
+	res, err = dg.ResolveTargets("app")
+	if err != nil {
+		t.Fatalf("resolving targets: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"docs", "lib", "app"}) {
+		t.Fatalf("regular dependency not pulled into targets: %v", res)
+	}
+
+	_, err = dg.ResolveTargets("X")
+	if !errors.Is(err, ErrUnknownNode) {
+		t.Fatalf("resolved unknown target: %v", err)
+	}
+}
+
+// TestResolveTargetsAlias tests that the targets get resolved through aliases.
+func TestResolveTargetsAlias(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("app", "lib")
+	dg.Add("lib")
+	dg.Add("docs")
+
+	err := dg.Alias("app", "application")
+	if err != nil {
+		t.Fatalf("aliasing node: %v", err)
+	}
+
+	res, err := dg.ResolveTargets("application")
+	if err != nil {
+		t.Fatalf("resolving aliased target: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"lib", "app"}) {
+		t.Fatalf("aliased target resolved incorrectly: %v", res)
+	}
+}
+
+// TestResolveTargetsUnknown tests that the unknown dependencies of the targets are left to the resolution.
+func TestResolveTargetsUnknown(t *testing.T) {
+	dg := NewDependencyGraph(WithUnknownHandler(func(_, _ string) error {
+		return nil
+	}))
+	dg.Add("app", "lib", "X")
+	dg.Add("lib")
+	dg.Add("docs")
+
+	res, err := dg.ResolveTargets("app")
+	if err != nil {
+		t.Fatalf("resolving targets with unknown dependency: %v", err)
+	}
+
+	if !slices.Equal(res, []string{"lib", "app"}) {
+		t.Fatalf("targets with unknown dependency resolved incorrectly: %v", res)
+	}
+}
@@ -0,0 +1,113 @@
+package depgraph
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+)
+
+// AddCost adds dep as a dependency of name, just like Add does, and assigns a cost to this dependency.
+// Dependencies added by other means cost 1.
+// Adding the same dependency again overrides its cost.
+// The cost must be a non-negative number; otherwise, AddCost panics.
+func (dg *DependencyGraph[T]) AddCost(name, dep T, cost float64) {
+	if cost < 0 || math.IsNaN(cost) {
+		panic("depgraph: dependency cost must be a non-negative number")
+	}
+
+	if len(dg.aliases) > 0 {
+		name, dep = dg.resolveAlias(name), dg.resolveAlias(dep)
+	}
+
+	dg.Add(name, dep)
+
+	edge := dg.edgeMap[name]
+	if edge.costs == nil {
+		edge.costs = map[T]float64{}
+	}
+
+	edge.costs[dep] = cost
+}
+
+// CheapestPath finds the cheapest path from one node to another, following the dependencies,
+// and returns the path (including both of its ends) along with its total cost.
+// If to is not reachable from from, it returns false.
+// When several paths share the minimal cost, any of them may be returned.
+func (dg *DependencyGraph[T]) CheapestPath(from, to T) ([]T, float64, bool) {
+	if _, ok := dg.edgeMap[from]; !ok {
+		return nil, 0, false
+	}
+
+	dist := map[T]float64{from: 0}
+	prev := map[T]T{}
+	done := map[T]struct{}{}
+	queue := &costQueue[T]{{node: from}}
+
+	for queue.Len() > 0 {
+		cur, _ := heap.Pop(queue).(costItem[T])
+
+		if _, ok := done[cur.node]; ok {
+			continue
+		}
+
+		done[cur.node] = struct{}{}
+
+		if cur.node == to {
+			path := []T{to}
+			for node := to; node != from; {
+				node = prev[node]
+				path = append(path, node)
+			}
+
+			slices.Reverse(path)
+
+			return path, cur.dist, true
+		}
+
+		edge, ok := dg.edgeMap[cur.node]
+		if !ok {
+			continue
+		}
+
+		for _, dep := range edge.depOrder {
+			cost, ok := edge.costs[dep]
+			if !ok {
+				cost = 1
+			}
+
+			if d, ok := dist[dep]; !ok || cur.dist+cost < d {
+				dist[dep] = cur.dist + cost
+				prev[dep] = cur.node
+				heap.Push(queue, costItem[T]{node: dep, dist: cur.dist + cost})
+			}
+		}
+	}
+
+	return nil, 0, false
+}
+
+// costItem is an entry of the Dijkstra's algorithm priority queue.
+type costItem[T comparable] struct {
+	node T
+	dist float64
+}
+
+// costQueue is a min-heap of cost items, ordered by their distance.
+type costQueue[T comparable] []costItem[T]
+
+func (q costQueue[T]) Len() int           { return len(q) }
+func (q costQueue[T]) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q costQueue[T]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *costQueue[T]) Push(x any) {
+	item, _ := x.(costItem[T])
+	*q = append(*q, item)
+}
+
+func (q *costQueue[T]) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return item
+}
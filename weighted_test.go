@@ -0,0 +1,104 @@
+package depgraph
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestCheapestPath tests that the cheapest path takes the edge costs into account.
+func TestCheapestPath(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "B")
+	dg.AddCost("A", "C", 5)
+	dg.AddCost("B", "D", 10)
+	dg.Add("C", "D")
+	dg.AddCost("D", "E", 0.5)
+	dg.Add("E")
+	dg.Add("F")
+
+	tbl := []struct {
+		from, to string
+		path     []string
+		cost     float64
+		ok       bool
+	}{
+		{from: "A", to: "E", path: []string{"A", "C", "D", "E"}, cost: 6.5, ok: true},
+		{from: "A", to: "B", path: []string{"A", "B"}, cost: 1, ok: true},
+		{from: "B", to: "E", path: []string{"B", "D", "E"}, cost: 10.5, ok: true},
+		{from: "D", to: "D", path: []string{"D"}, cost: 0, ok: true},
+		{from: "E", to: "A", ok: false},
+		{from: "A", to: "F", ok: false},
+		{from: "X", to: "A", ok: false},
+	}
+
+	for _, test := range tbl {
+		path, cost, ok := dg.CheapestPath(test.from, test.to)
+		if ok != test.ok || !slices.Equal(path, test.path) || cost != test.cost {
+			t.Fatalf("cheapest path from %v to %v found incorrectly: path = %v; cost = %v; found = %v",
+				test.from, test.to, path, cost, ok)
+		}
+	}
+
+	// Costs must survive snapshots.
+	snap := dg.Snapshot()
+	dg.AddCost("C", "D", 100)
+	dg.Restore(snap)
+
+	_, cost, _ := dg.CheapestPath("A", "E")
+	if cost != 6.5 {
+		t.Fatalf("cheapest path cost has not been restored: %v", cost)
+	}
+}
+
+// TestAddCostNegative tests that negative costs are rejected.
+func TestAddCostNegative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("added a dependency with negative cost")
+		}
+	}()
+
+	NewDependencyGraph[string]().AddCost("A", "B", -1)
+}
+
+// TestAddCostAlias tests that costs get assigned to the canonical names of both ends of a dependency.
+func TestAddCostAlias(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A")
+	dg.Add("X")
+
+	for _, alias := range [][2]string{{"A", "B"}, {"X", "Y"}} {
+		err := dg.Alias(alias[0], alias[1])
+		if err != nil {
+			t.Fatalf("adding alias: %v", err)
+		}
+	}
+
+	dg.AddCost("B", "Y", 3)
+
+	_, cost, ok := dg.CheapestPath("A", "X")
+	if !ok || cost != 3 {
+		t.Fatalf("cost of aliased dependency assigned incorrectly: %v, %v", cost, ok)
+	}
+
+	if deps := dg.Dependencies("A"); !slices.Equal(deps, []string{"X"}) {
+		t.Fatalf("aliased dependency added incorrectly: %v", deps)
+	}
+}
+
+// TestCheapestPathUnknown tests that unknown dependencies are traversed, but never lead anywhere.
+func TestCheapestPathUnknown(t *testing.T) {
+	dg := NewDependencyGraph[string]()
+	dg.Add("A", "X", "B")
+	dg.Add("B")
+	dg.Add("C")
+
+	if _, _, ok := dg.CheapestPath("A", "C"); ok {
+		t.Fatalf("path found between independent nodes")
+	}
+
+	path, cost, ok := dg.CheapestPath("A", "X")
+	if !ok || cost != 1 || !slices.Equal(path, []string{"A", "X"}) {
+		t.Fatalf("path to an unknown dependency found incorrectly: %v, %v, %v", path, cost, ok)
+	}
+}